@@ -1,128 +1,138 @@
-// Generate a Cann table for the English Premier League. https://en.wikipedia.org/wiki/Cann_table
+// Generate a Cann table for a football competition, the English Premier League by default. https://en.wikipedia.org/wiki/Cann_table
 // A Cann table shows the league positions with gaps to emphasise points differences between teams.
 // The standard league table standings are retrieved from api.football-data.org
 package cann
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"html/template"
-	"io"
-	"log"
 	"net/http"
-	"os"
+	"strings"
+
+	"github.com/mick4711/moh/footballdata"
+	"github.com/mick4711/moh/web"
 )
 
+const DefaultCompetition = "PL"
+
 type Points int
 
-// A Row contains the points and teams with those points
-type Row struct {
-	Points Points
-	Teams  string
+// An Entry contains the details displayed for a team in a Cann table row
+type Entry struct {
+	TeamID   int
+	Position int
+	Name     string
+	Played   int
+	GoalDiff int
 }
 
-// A Team contains details for a team.
-type Team struct {
-	ID        int    `json:"id"`
-	ShortName string `json:"shortName"`
+// String formats the entry as [Position]Team(Played, Goal Diff)
+func (e Entry) String() string {
+	return fmt.Sprintf("[%d]%s(%d, %+d)", e.Position, e.Name, e.Played, e.GoalDiff)
 }
 
-// A TableRow contains details for a standings table row.
-type TableRow struct {
-	Team     Team   `json:"team"`
-	Position int    `json:"position"`
-	Played   int    `json:"playedGames"`
-	Points   Points `json:"points"`
-	GoalDiff int    `json:"goalDifference"`
+// A Row contains the points and teams with those points
+type Row struct {
+	Points Points
+	Teams  []Entry
 }
 
-// A Standings contains a table of Rows, i.e. teams and points.
-type Standings struct {
-	Table []TableRow `json:"table"`
+// A Placing is where a team sits on the Cann table
+type Placing struct {
+	Position     int    `json:"position"`
+	Points       Points `json:"points"`
+	BehindLeader Points `json:"behind_leader"`
 }
 
-// DataResponse contains the Standings
-type DataResponse struct {
-	Standings []Standings `json:"standings"`
+// A Page contains the data for the Cann table template
+type Page struct {
+	Competition     string
+	CompetitionName string
+	Rows            []Row
 }
 
 // fetches the standard table standings, generates and outputs the Cann table
-func GenerateTable(w http.ResponseWriter, _ *http.Request) {
-	standings, err := getStandings()
+func GenerateTable(w http.ResponseWriter, req *http.Request) {
+	competition, ok := Competition(req)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	standings, err := getStandings(req.Context(), competition)
 	if err != nil {
-		returnError(err, w)
+		web.ReturnError(err, w)
 		return
 	}
 
-	cannTable, err := generateCann(standings)
+	cannTable, err := generateCann(standings.Table(footballdata.StandingTotal))
 	if err != nil {
-		returnError(err, w)
+		web.ReturnError(err, w)
 		return
 	}
 
-	if err := writeResponse(w, cannTable); err != nil {
-		returnError(err, w)
+	page := Page{
+		Competition:     competition,
+		CompetitionName: standings.Competition.Name,
+		Rows:            cannTable,
+	}
+
+	if err := writeResponse(w, page); err != nil {
+		web.ReturnError(err, w)
 		return
 	}
 }
 
-// TODO display empty page template with error message
-func returnError(err error, w http.ResponseWriter) {
-	log.Printf("\n*********** FATAL ERROR ********** [%s]\n", err)
-	w.WriteHeader(http.StatusInternalServerError)
-	fmt.Fprintln(w, err)
-}
+// Competition returns the competition code from the request path, PL if there is none.
+// ok is false when the competition is not supported.
+func Competition(req *http.Request) (competition string, ok bool) {
+	competition = strings.ToUpper(req.PathValue("competition"))
+	if competition == "" {
+		return DefaultCompetition, true
+	}
 
-// fetch standard table standings
-func getStandings() ([]byte, error) {
-	// configure request
-	url := `http://api.football-data.org/v4/competitions/PL/standings`
+	return competition, footballdata.Supported(competition)
+}
 
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+// Placings returns the Cann table placing of every team in a competition keyed by team id
+func Placings(ctx context.Context, competition string) (map[int]Placing, error) {
+	standings, err := getStandings(ctx, competition)
 	if err != nil {
-		return nil, fmt.Errorf("error creating standings request: %w", err)
+		return nil, err
 	}
 
-	// add API token to header
-	apiToken, ok := os.LookupEnv("API_TOKEN")
-	if !ok {
-		return nil, fmt.Errorf("environment variable -API_TOKEN- can not be read")
+	cannTable, err := generateCann(standings.Table(footballdata.StandingTotal))
+	if err != nil {
+		return nil, err
 	}
 
-	req.Header.Add("X-Auth-Token", apiToken)
-
-	// get the response body
-	client := http.Client{}
+	return placings(cannTable), nil
+}
 
-	resp, err := client.Do(req)
+// fetch the standings for a competition through the shared football-data client
+func getStandings(ctx context.Context, competition string) (*footballdata.StandingsResponse, error) {
+	client, err := footballdata.Shared()
 	if err != nil {
-		return nil, fmt.Errorf("error requesting standings: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("standings response status not OK: %v", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	standings, err := client.Standings(ctx, competition, footballdata.StandingsFilter{})
 	if err != nil {
-		return nil, fmt.Errorf("error reading standings response: %w", err)
+		return nil, fmt.Errorf("error requesting standings: %w", err)
 	}
 
-	return body, nil
+	return standings, nil
 }
 
 // generate Cann table from standard standings table
-func generateCann(standings []byte) ([]Row, error) {
-	// unmarshall json standings into DataResponse slice of TableRows
-	var dataResponse DataResponse
-	if err := json.Unmarshal(standings, &dataResponse); err != nil {
-		return nil, fmt.Errorf("error unmarshalling json from standings response:%w", err)
+func generateCann(standingsTable []footballdata.TableRow) ([]Row, error) {
+	if len(standingsTable) == 0 {
+		return nil, fmt.Errorf("standings table is empty")
 	}
 
-	standingsTable := dataResponse.Standings[0].Table
-	maxPoints := standingsTable[0].Points
-	minPoints := standingsTable[len(standingsTable)-1].Points
+	maxPoints := Points(standingsTable[0].Points)
+	minPoints := Points(standingsTable[len(standingsTable)-1].Points)
 
 	// generate an empty Cann table with the correct number of rows, set points values
 	cannTable := make([]Row, maxPoints-minPoints+1)
@@ -130,22 +140,42 @@ func generateCann(standings []byte) ([]Row, error) {
 		cannTable[i].Points = maxPoints - Points(i)
 	}
 
-	const rowFormat = "[%d]%s(%d, %+d)"
-
 	// loop thru standard table and assign team names and details to their point values in the Cann table
 	for _, row := range standingsTable {
-		index := maxPoints - row.Points
-		rowData := fmt.Sprintf(rowFormat, row.Position, row.Team.ShortName, row.Played, row.GoalDiff)
-		cannTable[index].Teams += fmt.Sprintf(" - %v", rowData)
+		index := maxPoints - Points(row.Points)
+		cannTable[index].Teams = append(cannTable[index].Teams, Entry{
+			TeamID:   row.Team.ID,
+			Position: row.Position,
+			Name:     row.Team.ShortName,
+			Played:   row.PlayedGames,
+			GoalDiff: row.GoalDifference,
+		})
 	}
 
 	return cannTable, nil
 }
 
+// placing of each team in the Cann table, points are measured from the leader's row
+func placings(cannTable []Row) map[int]Placing {
+	teamPlacings := map[int]Placing{}
+
+	for _, row := range cannTable {
+		for _, entry := range row.Teams {
+			teamPlacings[entry.TeamID] = Placing{
+				Position:     entry.Position,
+				Points:       row.Points,
+				BehindLeader: cannTable[0].Points - row.Points,
+			}
+		}
+	}
+
+	return teamPlacings
+}
+
 // write Cann table to response
-func writeResponse(w http.ResponseWriter, cannTable []Row) error {
+func writeResponse(w http.ResponseWriter, page Page) error {
 	cannTemplate := template.Must(template.ParseFiles("cann/CannTemplate.html"))
-	if err := cannTemplate.Execute(w, cannTable); err != nil {
+	if err := cannTemplate.Execute(w, page); err != nil {
 		return fmt.Errorf("error executing cannTemplate: %w", err)
 	}
 
@@ -1,35 +1,43 @@
 package cann
 
 import (
+	"encoding/json"
 	"log"
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/mick4711/moh/footballdata"
 )
 
 func TestGenerateCann(t *testing.T) {
-	validStandings, err := os.ReadFile("standings_test.json")
+	standingsJSON, err := os.ReadFile("standings_test.json")
 	if err != nil {
 		log.Fatalln(err)
 	}
 
+	var validStandings footballdata.StandingsResponse
+	if err := json.Unmarshal(standingsJSON, &validStandings); err != nil {
+		log.Fatalln(err)
+	}
+
 	validCannTable := []Row{
-		{45, " - [1]Liverpool(20, -25)"},
-		{44, ""},
-		{43, ""},
-		{42, " - [2]Aston Villa(20, +16)"},
-		{41, ""},
-		{40, " - [3]Man City(19, +24) - [4]Arsenal(20, +17)"},
-		{39, " - [5]Tottenham(20, +13)"},
+		{45, []Entry{{64, 1, "Liverpool", 20, -25}}},
+		{44, nil},
+		{43, nil},
+		{42, []Entry{{58, 2, "Aston Villa", 20, 16}}},
+		{41, nil},
+		{40, []Entry{{65, 3, "Man City", 19, 24}, {57, 4, "Arsenal", 20, 17}}},
+		{39, []Entry{{73, 5, "Tottenham", 20, 13}}},
 	}
 
 	tests := []struct {
-		input    []byte
+		input    []footballdata.TableRow
 		want     []Row
 		hasError bool
 	}{
-		{validStandings, validCannTable, false},
-		{[]byte{}, []Row(nil), true},
+		{validStandings.Standings[0].Table, validCannTable, false},
+		{[]footballdata.TableRow{}, []Row(nil), true},
 	}
 
 	for _, test := range tests {
@@ -43,3 +51,42 @@ func TestGenerateCann(t *testing.T) {
 		}
 	}
 }
+
+func TestEntryString(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{Entry{64, 1, "Liverpool", 20, -25}, "[1]Liverpool(20, -25)"},
+		{Entry{73, 5, "Tottenham", 20, 13}, "[5]Tottenham(20, +13)"},
+		{Entry{1044, 20, "Bournemouth", 19, 0}, "[20]Bournemouth(19, +0)"},
+	}
+
+	for _, test := range tests {
+		if got := test.entry.String(); got != test.want {
+			t.Errorf("Entry.String() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestPlacings(t *testing.T) {
+	cannTable := []Row{
+		{45, []Entry{{64, 1, "Liverpool", 20, -25}}},
+		{44, nil},
+		{43, nil},
+		{42, []Entry{{58, 2, "Aston Villa", 20, 16}}},
+		{41, nil},
+		{40, []Entry{{65, 3, "Man City", 19, 24}, {57, 4, "Arsenal", 20, 17}}},
+	}
+
+	want := map[int]Placing{
+		64: {1, 45, 0},
+		58: {2, 42, 3},
+		65: {3, 40, 5},
+		57: {4, 40, 5},
+	}
+
+	if got := placings(cannTable); !reflect.DeepEqual(got, want) {
+		t.Errorf("placings()\ngot :%v, \nwant:%v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+// adds CORS headers to responses for allowed origins and answers preflight requests
+// for any route registered on a mux, so the json endpoints can be called from browser apps.
+package cors
+
+import (
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// origins allowed when environment variable "cors_origins" is not set: the Vercel app and local development
+const DefaultOrigins = "https://fpl-react.vercel.app, http://localhost:3000"
+
+// A Config lists what cross-origin requests may do
+type Config struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration // how long a browser may cache a preflight response
+}
+
+// DefaultConfig allows GET requests from the origins in environment variable "cors_origins",
+// a comma separated list, or DefaultOrigins
+func DefaultConfig() Config {
+	origins, ok := os.LookupEnv("cors_origins")
+	if !ok {
+		origins = DefaultOrigins
+	}
+
+	return Config{
+		AllowedOrigins: split(origins),
+		AllowedMethods: []string{http.MethodGet},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         time.Hour,
+	}
+}
+
+// split a comma separated list
+func split(list string) []string {
+	var values []string
+
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Handler serves requests with mux, adding CORS headers when the Origin is allowed.
+// Preflight requests are answered for any route mux has for the requested method, they are
+// forbidden for other origins and methods, and not found for routes mux does not have.
+func Handler(mux *http.ServeMux, config Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := slices.Contains(config.AllowedOrigins, origin)
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method != http.MethodOptions || requestMethod == "" {
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+
+			mux.ServeHTTP(w, r)
+
+			return
+		}
+
+		// preflight
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		if !allowed || !slices.Contains(config.AllowedMethods, requestMethod) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		route := r.Clone(r.Context())
+		route.Method = requestMethod
+
+		if _, pattern := mux.Handler(route); pattern == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
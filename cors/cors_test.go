@@ -0,0 +1,110 @@
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const allowedOrigin = "https://fpl-react.vercel.app"
+
+func testHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /fpl", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+	mux.HandleFunc("GET /scorers/{competition}/json", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+
+	return Handler(mux, Config{
+		AllowedOrigins: []string{allowedOrigin, "http://localhost:3000"},
+		AllowedMethods: []string{http.MethodGet},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         time.Hour,
+	})
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		scenario      string
+		method        string
+		path          string
+		origin        string
+		requestMethod string
+		wantStatus    int
+		wantOrigin    string
+	}{
+		{"same origin", http.MethodGet, "/fpl", "", "", http.StatusOK, ""},
+		{"allowed origin", http.MethodGet, "/fpl", allowedOrigin, "", http.StatusOK, allowedOrigin},
+		{"allowed localhost", http.MethodGet, "/fpl", "http://localhost:3000", "", http.StatusOK, "http://localhost:3000"},
+		{"rejected origin", http.MethodGet, "/fpl", "https://example.com", "", http.StatusOK, ""},
+		{"preflight", http.MethodOptions, "/fpl", allowedOrigin, http.MethodGet, http.StatusNoContent, allowedOrigin},
+		{"preflight with path value", http.MethodOptions, "/scorers/PL/json", allowedOrigin, http.MethodGet, http.StatusNoContent, allowedOrigin},
+		{"preflight rejected origin", http.MethodOptions, "/fpl", "https://example.com", http.MethodGet, http.StatusForbidden, ""},
+		{"preflight rejected method", http.MethodOptions, "/fpl", allowedOrigin, http.MethodPost, http.StatusForbidden, ""},
+		{"preflight unknown route", http.MethodOptions, "/nowhere", allowedOrigin, http.MethodGet, http.StatusNotFound, ""},
+		{"options without preflight", http.MethodOptions, "/fpl", allowedOrigin, "", http.StatusMethodNotAllowed, allowedOrigin},
+	}
+
+	handler := testHandler()
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, http.NoBody)
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+
+		if test.requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", test.requestMethod)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != test.wantStatus {
+			t.Errorf("%s: status = %d, want %d", test.scenario, rec.Code, test.wantStatus)
+		}
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != test.wantOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", test.scenario, got, test.wantOrigin)
+		}
+
+		if test.origin != "" && rec.Header().Get("Vary") != "Origin" {
+			t.Errorf("%s: Vary = %q, want Origin first", test.scenario, rec.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestPreflightHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/fpl", http.NoBody)
+	req.Header.Set("Origin", allowedOrigin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	rec := httptest.NewRecorder()
+	testHandler().ServeHTTP(rec, req)
+
+	want := map[string]string{
+		"Access-Control-Allow-Methods": "GET",
+		"Access-Control-Allow-Headers": "Content-Type",
+		"Access-Control-Max-Age":       "3600",
+	}
+
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
+
+func TestDefaultConfig(t *testing.T) {
+	t.Setenv("cors_origins", " https://example.vercel.app ,,http://localhost:5173")
+
+	want := []string{"https://example.vercel.app", "http://localhost:5173"}
+	if got := DefaultConfig().AllowedOrigins; !reflect.DeepEqual(got, want) {
+		t.Errorf("DefaultConfig() AllowedOrigins = %v, want %v", got, want)
+	}
+}
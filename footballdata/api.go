@@ -0,0 +1,224 @@
+package footballdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateFormat = "2006-01-02" // date format of football-data query parameters
+
+// SupportedCompetitions are the codes of the competitions available on the football-data free tier
+var SupportedCompetitions = []string{"PL", "ELC", "BL1", "SA", "PD", "FL1", "DED", "PPL", "CL", "EC", "WC", "BSA"}
+
+// Supported reports whether code is one of the SupportedCompetitions
+func Supported(code string) bool {
+	return slices.Contains(SupportedCompetitions, code)
+}
+
+// A StandingsFilter selects the season, matchday or date of the standings, zero values are ignored.
+type StandingsFilter struct {
+	Season   int // starting year of the season
+	Matchday int
+	Date     time.Time
+}
+
+func (f StandingsFilter) query() url.Values {
+	query := url.Values{}
+
+	if f.Season > 0 {
+		query.Set("season", strconv.Itoa(f.Season))
+	}
+
+	if f.Matchday > 0 {
+		query.Set("matchday", strconv.Itoa(f.Matchday))
+	}
+
+	if !f.Date.IsZero() {
+		query.Set("date", f.Date.Format(dateFormat))
+	}
+
+	return query
+}
+
+// A MatchFilter selects matches by date range, matchday and status, zero values are ignored.
+// Competitions and Limit only apply to a team's matches.
+type MatchFilter struct {
+	DateFrom     time.Time
+	DateTo       time.Time
+	Matchday     int
+	Status       []string
+	Competitions []string
+	Limit        int
+}
+
+func (f MatchFilter) query() url.Values {
+	query := url.Values{}
+
+	if !f.DateFrom.IsZero() {
+		query.Set("dateFrom", f.DateFrom.Format(dateFormat))
+	}
+
+	if !f.DateTo.IsZero() {
+		query.Set("dateTo", f.DateTo.Format(dateFormat))
+	}
+
+	if f.Matchday > 0 {
+		query.Set("matchday", strconv.Itoa(f.Matchday))
+	}
+
+	if len(f.Status) > 0 {
+		query.Set("status", strings.Join(f.Status, ","))
+	}
+
+	if len(f.Competitions) > 0 {
+		query.Set("competitions", strings.Join(f.Competitions, ","))
+	}
+
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
+	}
+
+	return query
+}
+
+// Competitions returns the competitions available to the token
+func (c *Client) Competitions(ctx context.Context) ([]Competition, error) {
+	var response struct {
+		Competitions []Competition `json:"competitions"`
+	}
+
+	if err := c.getJSON(ctx, "/competitions", nil, 0, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Competitions, nil
+}
+
+// Competition returns the competition with code, e.g. PL
+func (c *Client) Competition(ctx context.Context, code string) (*Competition, error) {
+	var competition Competition
+	if err := c.getJSON(ctx, "/competitions/"+url.PathEscape(code), nil, 0, &competition); err != nil {
+		return nil, err
+	}
+
+	return &competition, nil
+}
+
+// Standings returns all standings types, total, home and away, for a competition
+func (c *Client) Standings(ctx context.Context, code string, filter StandingsFilter) (*StandingsResponse, error) {
+	var standings StandingsResponse
+
+	path := "/competitions/" + url.PathEscape(code) + "/standings"
+	if err := c.getJSON(ctx, path, filter.query(), CacheTTL, &standings); err != nil {
+		return nil, err
+	}
+
+	return &standings, nil
+}
+
+// Matches returns the matches of a competition selected by filter
+func (c *Client) Matches(ctx context.Context, code string, filter MatchFilter) ([]Match, error) {
+	var response MatchesResponse
+
+	path := "/competitions/" + url.PathEscape(code) + "/matches"
+	if err := c.getJSON(ctx, path, filter.query(), 0, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Matches, nil
+}
+
+// TeamMatches returns the matches of a team in all its competitions, selected by filter
+func (c *Client) TeamMatches(ctx context.Context, id int, filter MatchFilter) ([]Match, error) {
+	var response MatchesResponse
+
+	path := "/teams/" + strconv.Itoa(id) + "/matches"
+	if err := c.getJSON(ctx, path, filter.query(), 0, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Matches, nil
+}
+
+// Teams returns the teams in a competition
+func (c *Client) Teams(ctx context.Context, code string) ([]Team, error) {
+	var response TeamsResponse
+
+	path := "/competitions/" + url.PathEscape(code) + "/teams"
+	if err := c.getJSON(ctx, path, nil, 0, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Teams, nil
+}
+
+// Team returns a team including its squad and running competitions
+func (c *Client) Team(ctx context.Context, id int) (*Team, error) {
+	var team Team
+	if err := c.getJSON(ctx, "/teams/"+strconv.Itoa(id), nil, 0, &team); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// Squad returns the players of a team
+func (c *Client) Squad(ctx context.Context, id int) ([]Person, error) {
+	team, err := c.Team(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return team.Squad, nil
+}
+
+// Scorers returns the top scorers of a competition, limit 0 uses the API default of 10
+func (c *Client) Scorers(ctx context.Context, code string, limit int) (*ScorersResponse, error) {
+	var response ScorersResponse
+
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	path := "/competitions/" + url.PathEscape(code) + "/scorers"
+	if err := c.getJSON(ctx, path, query, CacheTTL, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// get path with query and unmarshal the json response into v, responses are cached for ttl when it is not 0
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, ttl time.Duration, v any) error {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var (
+		body []byte
+		err  error
+	)
+
+	if ttl > 0 {
+		body, err = c.GetCached(ctx, path, ttl)
+	} else {
+		body, err = c.Get(ctx, path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error unmarshalling json from %s response: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package footballdata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fixtureServer serves the recorded responses in testdata, keyed by request path.
+// The query of the last request is sent to queries.
+func fixtureServer(t *testing.T, queries chan<- url.Values) *httptest.Server {
+	t.Helper()
+
+	fixtures := map[string]string{
+		"/competitions":              "testdata/competitions.json",
+		"/competitions/PL":           "testdata/competition.json",
+		"/competitions/PL/standings": "testdata/standings.json",
+		"/competitions/PL/matches":   "testdata/matches.json",
+		"/competitions/PL/teams":     "testdata/teams.json",
+		"/competitions/PL/scorers":   "testdata/scorers.json",
+		"/teams/64":                  "testdata/team.json",
+		"/teams/64/matches":          "testdata/team_matches.json",
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if queries != nil {
+			queries <- r.URL.Query()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, fixture)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestCompetitions(t *testing.T) {
+	client := NewClient(fixtureServer(t, nil).URL, testToken)
+
+	competitions, err := client.Competitions(context.Background())
+	if err != nil {
+		t.Fatalf("Competitions() err = %v", err)
+	}
+
+	if len(competitions) != 2 || competitions[0].Code != "PL" || competitions[1].Type != "CUP" {
+		t.Errorf("Competitions() = %+v", competitions)
+	}
+
+	competition, err := client.Competition(context.Background(), "PL")
+	if err != nil {
+		t.Fatalf("Competition() err = %v", err)
+	}
+
+	if competition.Name != "Premier League" || competition.CurrentSeason.CurrentMatchday != 20 {
+		t.Errorf("Competition() = %+v", competition)
+	}
+}
+
+func TestStandings(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	client := NewClient(fixtureServer(t, queries).URL, testToken)
+
+	filter := StandingsFilter{Season: 2024, Matchday: 20, Date: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)}
+
+	standings, err := client.Standings(context.Background(), "PL", filter)
+	if err != nil {
+		t.Fatalf("Standings() err = %v", err)
+	}
+
+	query := <-queries
+	if query.Get("season") != "2024" || query.Get("matchday") != "20" || query.Get("date") != "2025-01-06" {
+		t.Errorf("Standings() query = %v", query)
+	}
+
+	tests := []struct {
+		standingType string
+		leader       string
+		played       int
+	}{
+		{StandingTotal, "Liverpool", 19},
+		{StandingHome, "Liverpool", 10},
+		{StandingAway, "Liverpool", 9},
+	}
+
+	for _, test := range tests {
+		table := standings.Table(test.standingType)
+		if len(table) != 4 {
+			t.Fatalf("Table(%s) rows = %d, want 4", test.standingType, len(table))
+		}
+
+		if table[0].Team.ShortName != test.leader || table[0].PlayedGames != test.played {
+			t.Errorf("Table(%s)[0] = %+v", test.standingType, table[0])
+		}
+	}
+
+	if total := standings.Table(StandingTotal); total[3].GoalDifference != 12 || total[3].Points != 24 {
+		t.Errorf("Table(TOTAL)[3] = %+v, want goal difference 12 and 24 points", total[3])
+	}
+
+	if standings.Table("GROUP_A") != nil {
+		t.Error("Table(GROUP_A) is not nil")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	client := NewClient(fixtureServer(t, queries).URL, testToken)
+
+	filter := MatchFilter{
+		DateFrom: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		Status:   []string{StatusScheduled, StatusTimed},
+	}
+
+	matches, err := client.Matches(context.Background(), "PL", filter)
+	if err != nil {
+		t.Fatalf("Matches() err = %v", err)
+	}
+
+	query := <-queries
+	if query.Get("dateFrom") != "2025-01-04" || query.Get("dateTo") != "2025-01-15" ||
+		query.Get("status") != "SCHEDULED,TIMED" || query.Has("matchday") {
+		t.Errorf("Matches() query = %v", query)
+	}
+
+	if len(matches) != 4 {
+		t.Fatalf("Matches() = %d matches, want 4", len(matches))
+	}
+
+	played := matches[0]
+	if played.HomeTeam.ShortName != "Arsenal" || *played.Score.FullTime.Home != 2 || *played.Score.FullTime.Away != 1 ||
+		!played.UTCDate.Equal(time.Date(2025, 1, 4, 17, 30, 0, 0, time.UTC)) {
+		t.Errorf("Matches()[0] = %+v", played)
+	}
+
+	if upcoming := matches[3]; upcoming.Status != StatusScheduled || upcoming.Score.FullTime.Home != nil {
+		t.Errorf("Matches()[3] = %+v, want scheduled with no score", upcoming)
+	}
+}
+
+func TestTeamMatches(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	client := NewClient(fixtureServer(t, queries).URL, testToken)
+
+	filter := MatchFilter{Status: []string{StatusFinished}, Competitions: []string{"PL"}, Limit: 2}
+
+	matches, err := client.TeamMatches(context.Background(), 64, filter)
+	if err != nil {
+		t.Fatalf("TeamMatches() err = %v", err)
+	}
+
+	query := <-queries
+	if query.Get("status") != "FINISHED" || query.Get("competitions") != "PL" || query.Get("limit") != "2" {
+		t.Errorf("TeamMatches() query = %v", query)
+	}
+
+	if len(matches) != 2 || matches[1].AwayTeam.ID != 64 || matches[1].Score.Winner != "DRAW" {
+		t.Errorf("TeamMatches() = %+v", matches)
+	}
+}
+
+func TestTeams(t *testing.T) {
+	client := NewClient(fixtureServer(t, nil).URL, testToken)
+
+	teams, err := client.Teams(context.Background(), "PL")
+	if err != nil {
+		t.Fatalf("Teams() err = %v", err)
+	}
+
+	if len(teams) != 4 || teams[0].TLA != "LIV" {
+		t.Errorf("Teams() = %+v", teams)
+	}
+
+	team, err := client.Team(context.Background(), 64)
+	if err != nil {
+		t.Fatalf("Team() err = %v", err)
+	}
+
+	if team.Venue != "Anfield" || team.Coach.Name != "Arne Slot" || len(team.RunningCompetitions) != 2 {
+		t.Errorf("Team() = %+v", team)
+	}
+
+	squad, err := client.Squad(context.Background(), 64)
+	if err != nil {
+		t.Fatalf("Squad() err = %v", err)
+	}
+
+	if len(squad) != 4 || squad[3].Name != "Mohamed Salah" || squad[3].Position != "Offence" {
+		t.Errorf("Squad() = %+v", squad)
+	}
+
+	if _, err := client.Team(context.Background(), 1); err == nil {
+		t.Error("Team(1) err = nil, want not found error")
+	}
+}
+
+func TestScorers(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	client := NewClient(fixtureServer(t, queries).URL, testToken)
+
+	response, err := client.Scorers(context.Background(), "PL", 3)
+	if err != nil {
+		t.Fatalf("Scorers() err = %v", err)
+	}
+
+	if query := <-queries; query.Get("limit") != "3" {
+		t.Errorf("Scorers() query = %v, want limit=3", query)
+	}
+
+	if response.Competition.Code != "PL" || response.Season.StartDate != "2024-08-16" {
+		t.Errorf("Scorers() competition = %+v, season = %+v", response.Competition, response.Season)
+	}
+
+	scorers := response.Scorers
+	if len(scorers) != 3 {
+		t.Fatalf("Scorers() = %d scorers, want 3", len(scorers))
+	}
+
+	if salah := scorers[0]; salah.Goals != 18 || *salah.Assists != 13 || *salah.Penalties != 4 || salah.Team.ID != 64 {
+		t.Errorf("Scorers()[0] = %+v", salah)
+	}
+
+	if haaland := scorers[1]; haaland.Assists != nil {
+		t.Errorf("Scorers()[1] assists = %v, want nil", *haaland.Assists)
+	}
+}
@@ -0,0 +1,45 @@
+package footballdata
+
+import (
+	"sync"
+	"time"
+)
+
+// CacheTTL is how long standings and scorers responses are reused before being requested again
+const CacheTTL = 5 * time.Minute
+
+type cacheEntry struct {
+	body    []byte
+	fetched time.Time
+}
+
+// a cache of response bodies keyed by request path
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+func newCache() *cache {
+	return &cache{entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+// get returns the body cached for path if it is younger than ttl
+func (c *cache) get(path string, ttl time.Duration) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[path]
+	if !ok || c.now().Sub(entry.fetched) >= ttl {
+		return nil, false
+	}
+
+	return entry.body, true
+}
+
+func (c *cache) put(path string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[path] = cacheEntry{body: body, fetched: c.now()}
+}
@@ -0,0 +1,69 @@
+package footballdata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStandingsAndScorersAreCached(t *testing.T) {
+	var calls atomic.Int32
+
+	fixtures := fixtureServer(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fixtures.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, testToken)
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := client.Standings(ctx, "PL", StandingsFilter{}); err != nil {
+			t.Fatalf("Standings() err = %v", err)
+		}
+
+		if _, err := client.Scorers(ctx, "PL", 3); err != nil {
+			t.Fatalf("Scorers() err = %v", err)
+		}
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("requests = %d, want 2 with cached responses", calls.Load())
+	}
+
+	// a different query is cached separately
+	if _, err := client.Standings(ctx, "PL", StandingsFilter{Matchday: 1}); err != nil {
+		t.Fatalf("Standings(matchday 1) err = %v", err)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3 after a new query", calls.Load())
+	}
+
+	// expired entries are requested again
+	client.cache.now = func() time.Time { return time.Now().Add(CacheTTL) }
+
+	if _, err := client.Standings(ctx, "PL", StandingsFilter{}); err != nil {
+		t.Fatalf("Standings() err = %v", err)
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("requests = %d, want 4 after expiry", calls.Load())
+	}
+
+	// other endpoints are not cached
+	for range 2 {
+		if _, err := client.Matches(ctx, "PL", MatchFilter{}); err != nil {
+			t.Fatalf("Matches() err = %v", err)
+		}
+	}
+
+	if calls.Load() != 6 {
+		t.Errorf("requests = %d, want 6 with uncached matches", calls.Load())
+	}
+}
@@ -0,0 +1,175 @@
+// Client for the football-data.org v4 API shared by every page that needs football data.
+// Requests have a timeout and are throttled by a token bucket driven by the rate limit headers
+// X-Requests-Available-Minute and X-RequestCounter-Reset that football-data.org sends with every response.
+package footballdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	BaseURL        = "https://api.football-data.org/v4"
+	RequestTimeout = 10 * time.Second
+	maxRetries     = 2 // retries after a 429 Too Many Requests response
+)
+
+// typed errors for the football-data.org error responses, match with errors.Is
+var (
+	ErrBadRequest  = errors.New("football-data bad request")
+	ErrForbidden   = errors.New("football-data forbidden, resource not available with this token")
+	ErrRateLimited = errors.New("football-data rate limit reached")
+)
+
+// An APIError contains the details of a football-data.org response that was not OK.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("football-data response status not OK: %d %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to one of the typed errors
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+
+	return nil
+}
+
+// A Client makes throttled requests to football-data.org.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	limiter    *limiter
+	cache      *cache
+}
+
+// NewClient returns a client for the API at baseURL authenticated with token
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: RequestTimeout},
+		limiter:    newLimiter(),
+		cache:      newCache(),
+	}
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   *Client
+)
+
+// Shared returns the client used for every football-data call in the project.
+// It is configured from environment variable API_TOKEN on first use.
+func Shared() (*Client, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	if shared != nil {
+		return shared, nil
+	}
+
+	apiToken, ok := os.LookupEnv("API_TOKEN")
+	if !ok {
+		return nil, fmt.Errorf("environment variable -API_TOKEN- can not be read")
+	}
+
+	shared = NewClient(BaseURL, apiToken)
+
+	return shared, nil
+}
+
+// Get requests path, relative to the base URL, and returns the response body.
+// A 429 response drains the limiter so the retry waits for the counter reset.
+func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("error waiting for football-data rate limit: %w", err)
+		}
+
+		body, err := c.do(ctx, path)
+		if errors.Is(err, ErrRateLimited) && attempt < maxRetries {
+			continue
+		}
+
+		return body, err
+	}
+}
+
+// GetCached returns the body cached for path when it is younger than ttl, otherwise it is requested with Get
+func (c *Client) GetCached(ctx context.Context, path string, ttl time.Duration) ([]byte, error) {
+	if body, ok := c.cache.get(path, ttl); ok {
+		return body, nil
+	}
+
+	body, err := c.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.put(path, body)
+
+	return body, nil
+}
+
+// make a single request and update the limiter from the response headers
+func (c *Client) do(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating football-data request: %w", err)
+	}
+
+	req.Header.Add("X-Auth-Token", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	c.limiter.update(resp.Header, resp.StatusCode == http.StatusTooManyRequests)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s response: %w", path, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// build an APIError using the message from the json error body when there is one
+func newAPIError(statusCode int, body []byte) *APIError {
+	var errorResponse struct {
+		Message string `json:"message"`
+	}
+
+	message := http.StatusText(statusCode)
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Message != "" {
+		message = errorResponse.Message
+	}
+
+	return &APIError{StatusCode: statusCode, Message: message}
+}
@@ -0,0 +1,165 @@
+package footballdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testToken = "test-token"
+
+func TestGet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != testToken {
+			t.Errorf("X-Auth-Token = %q, want %q", r.Header.Get("X-Auth-Token"), testToken)
+		}
+
+		if r.URL.Path != "/competitions/PL/standings" {
+			t.Errorf("path = %q, want /competitions/PL/standings", r.URL.Path)
+		}
+
+		w.Header().Set("X-Requests-Available-Minute", "7")
+		w.Header().Set("X-RequestCounter-Reset", "42")
+		fmt.Fprint(w, `{"standings":[]}`)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL+"/", testToken)
+
+	body, err := client.Get(context.Background(), "/competitions/PL/standings")
+	if err != nil {
+		t.Fatalf("Get() err = %v, want nil", err)
+	}
+
+	if string(body) != `{"standings":[]}` {
+		t.Errorf("Get() body = %s", body)
+	}
+
+	// limiter takes its state from the response headers
+	if client.limiter.available != 7 {
+		t.Errorf("limiter available = %d, want 7", client.limiter.available)
+	}
+
+	if wait := time.Until(client.limiter.reset); wait < 40*time.Second || wait > 42*time.Second {
+		t.Errorf("limiter reset in %v, want 42s", wait)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		status  int
+		want    error
+		message string
+	}{
+		{http.StatusBadRequest, ErrBadRequest, "Your request is invalid."},
+		{http.StatusForbidden, ErrForbidden, "The resource you are looking for is restricted."},
+		{http.StatusNotFound, nil, "Not Found"},
+	}
+
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(test.status)
+
+			if test.want != nil {
+				fmt.Fprintf(w, `{"message":%q,"errorCode":%d}`, test.message, test.status)
+			}
+		}))
+
+		_, err := NewClient(ts.URL, testToken).Get(context.Background(), "/teams/1")
+		ts.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Get() status %d err = %v, want *APIError", test.status, err)
+		}
+
+		if apiErr.StatusCode != test.status || apiErr.Message != test.message {
+			t.Errorf("Get() status %d err = %#v", test.status, apiErr)
+		}
+
+		if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("Get() status %d err = %v, want errors.Is %v", test.status, err, test.want)
+		}
+	}
+}
+
+func TestGetRetriesAfterRateLimit(t *testing.T) {
+	var calls atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RequestCounter-Reset", "0")
+
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-Requests-Available-Minute", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"You reached your request limit. Wait 0 seconds.","errorCode":429}`)
+
+			return
+		}
+
+		w.Header().Set("X-Requests-Available-Minute", "9")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	body, err := NewClient(ts.URL, testToken).Get(context.Background(), "/competitions")
+	if err != nil {
+		t.Fatalf("Get() err = %v, want nil after retry", err)
+	}
+
+	if string(body) != `{}` || calls.Load() != 2 {
+		t.Errorf("Get() body = %s after %d calls, want {} after 2 calls", body, calls.Load())
+	}
+}
+
+func TestGetRateLimitExhausted(t *testing.T) {
+	var calls atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RequestCounter-Reset", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	_, err := NewClient(ts.URL, testToken).Get(context.Background(), "/competitions")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Get() err = %v, want ErrRateLimited", err)
+	}
+
+	if calls.Load() != maxRetries+1 {
+		t.Errorf("Get() made %d calls, want %d", calls.Load(), maxRetries+1)
+	}
+}
+
+func TestLimiterWaitsForReset(t *testing.T) {
+	l := newLimiter()
+	l.update(http.Header{
+		"X-Requests-Available-Minute": {"0"},
+		"X-Requestcounter-Reset":      {"60"},
+	}, false)
+
+	// no tokens left so wait blocks until the context deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// once the reset time has passed the bucket is refilled
+	l.now = func() time.Time { return time.Now().Add(time.Minute) }
+
+	if err := l.wait(context.Background()); err != nil {
+		t.Errorf("wait() after reset err = %v, want nil", err)
+	}
+
+	if l.available != defaultRequestsPerMinute-1 {
+		t.Errorf("available after refill = %d, want %d", l.available, defaultRequestsPerMinute-1)
+	}
+}
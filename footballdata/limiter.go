@@ -0,0 +1,87 @@
+package footballdata
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestsPerMinute = 10 // free tier allowance
+	limiterWindow            = time.Minute
+)
+
+// A limiter is a token bucket refilled every window. The headers of each response
+// overwrite the local count so the bucket tracks what the server has actually counted.
+type limiter struct {
+	mu        sync.Mutex
+	capacity  int
+	available int
+	reset     time.Time
+	now       func() time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{
+		capacity:  defaultRequestsPerMinute,
+		available: defaultRequestsPerMinute,
+		now:       time.Now,
+	}
+}
+
+// wait blocks until a token is available or ctx is done
+func (l *limiter) wait(ctx context.Context) error {
+	for {
+		delay := l.take()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take a token, or return how long to wait before the bucket is refilled
+func (l *limiter) take() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !now.Before(l.reset) {
+		l.available = l.capacity
+		l.reset = now.Add(limiterWindow)
+	}
+
+	if l.available > 0 {
+		l.available--
+		return 0
+	}
+
+	return l.reset.Sub(now)
+}
+
+// update the bucket from the rate limit headers, a rate limited response empties it
+func (l *limiter) update(header http.Header, rateLimited bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if available, err := strconv.Atoi(header.Get("X-Requests-Available-Minute")); err == nil {
+		l.available = available
+	}
+
+	if reset, err := strconv.Atoi(header.Get("X-RequestCounter-Reset")); err == nil {
+		l.reset = l.now().Add(time.Duration(reset) * time.Second)
+	}
+
+	if rateLimited {
+		l.available = 0
+	}
+}
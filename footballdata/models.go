@@ -0,0 +1,184 @@
+package footballdata
+
+import "time"
+
+// standing types returned by the standings endpoint
+const (
+	StandingTotal = "TOTAL"
+	StandingHome  = "HOME"
+	StandingAway  = "AWAY"
+)
+
+// match statuses used by the matches endpoint
+const (
+	StatusScheduled = "SCHEDULED"
+	StatusTimed     = "TIMED"
+	StatusInPlay    = "IN_PLAY"
+	StatusPaused    = "PAUSED"
+	StatusFinished  = "FINISHED"
+	StatusPostponed = "POSTPONED"
+	StatusSuspended = "SUSPENDED"
+	StatusCancelled = "CANCELLED"
+)
+
+// An Area is a country or region.
+type Area struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+	Flag string `json:"flag"`
+}
+
+// A Season contains the dates and current matchday of a competition season.
+type Season struct {
+	ID              int    `json:"id"`
+	StartDate       string `json:"startDate"`
+	EndDate         string `json:"endDate"`
+	CurrentMatchday int    `json:"currentMatchday"`
+	Winner          *Team  `json:"winner"`
+}
+
+// A Competition contains details for a league or cup.
+type Competition struct {
+	ID            int     `json:"id"`
+	Area          Area    `json:"area"`
+	Name          string  `json:"name"`
+	Code          string  `json:"code"`
+	Type          string  `json:"type"`
+	Emblem        string  `json:"emblem"`
+	Plan          string  `json:"plan"`
+	CurrentSeason *Season `json:"currentSeason"`
+}
+
+// A Person is a player or coach.
+type Person struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	DateOfBirth string `json:"dateOfBirth"`
+	Nationality string `json:"nationality"`
+	Position    string `json:"position"`
+	ShirtNumber int    `json:"shirtNumber"`
+	Section     string `json:"section"`
+}
+
+// A Team contains details for a team, the squad is only included by the team endpoint.
+type Team struct {
+	ID                  int           `json:"id"`
+	Name                string        `json:"name"`
+	ShortName           string        `json:"shortName"`
+	TLA                 string        `json:"tla"`
+	Crest               string        `json:"crest"`
+	Address             string        `json:"address"`
+	Website             string        `json:"website"`
+	Founded             int           `json:"founded"`
+	ClubColors          string        `json:"clubColors"`
+	Venue               string        `json:"venue"`
+	RunningCompetitions []Competition `json:"runningCompetitions"`
+	Coach               *Person       `json:"coach"`
+	Squad               []Person      `json:"squad"`
+}
+
+// A TableRow contains details for a standings table row.
+type TableRow struct {
+	Position       int    `json:"position"`
+	Team           Team   `json:"team"`
+	PlayedGames    int    `json:"playedGames"`
+	Form           string `json:"form"`
+	Won            int    `json:"won"`
+	Draw           int    `json:"draw"`
+	Lost           int    `json:"lost"`
+	Points         int    `json:"points"`
+	GoalsFor       int    `json:"goalsFor"`
+	GoalsAgainst   int    `json:"goalsAgainst"`
+	GoalDifference int    `json:"goalDifference"`
+}
+
+// A Standing is one table of a competition, e.g. the TOTAL, HOME or AWAY table or a cup group.
+type Standing struct {
+	Stage string     `json:"stage"`
+	Type  string     `json:"type"`
+	Group string     `json:"group"`
+	Table []TableRow `json:"table"`
+}
+
+// StandingsResponse contains every standings table for a competition season
+type StandingsResponse struct {
+	Area        Area        `json:"area"`
+	Competition Competition `json:"competition"`
+	Season      Season      `json:"season"`
+	Standings   []Standing  `json:"standings"`
+}
+
+// Table returns the rows of the first standing of standingType, nil if there is none
+func (s *StandingsResponse) Table(standingType string) []TableRow {
+	for _, standing := range s.Standings {
+		if standing.Type == standingType {
+			return standing.Table
+		}
+	}
+
+	return nil
+}
+
+// Goals scored by each team, nil until a match has started.
+type Goals struct {
+	Home *int `json:"home"`
+	Away *int `json:"away"`
+}
+
+// A Score contains the result of a match.
+type Score struct {
+	Winner   string `json:"winner"`
+	Duration string `json:"duration"`
+	FullTime Goals  `json:"fullTime"`
+	HalfTime Goals  `json:"halfTime"`
+}
+
+// A Match contains details for a fixture or result.
+type Match struct {
+	ID          int         `json:"id"`
+	Competition Competition `json:"competition"`
+	Season      Season      `json:"season"`
+	UTCDate     time.Time   `json:"utcDate"`
+	Status      string      `json:"status"`
+	Matchday    int         `json:"matchday"`
+	Stage       string      `json:"stage"`
+	Group       string      `json:"group"`
+	HomeTeam    Team        `json:"homeTeam"`
+	AwayTeam    Team        `json:"awayTeam"`
+	Score       Score       `json:"score"`
+}
+
+// MatchesResponse contains the matches for a competition
+type MatchesResponse struct {
+	Competition Competition `json:"competition"`
+	Matches     []Match     `json:"matches"`
+}
+
+// TeamsResponse contains the teams in a competition season
+type TeamsResponse struct {
+	Count       int         `json:"count"`
+	Competition Competition `json:"competition"`
+	Season      Season      `json:"season"`
+	Teams       []Team      `json:"teams"`
+}
+
+// A Scorer contains the goal statistics for a player, assists and penalties are nil when not recorded.
+type Scorer struct {
+	Player        Person `json:"player"`
+	Team          Team   `json:"team"`
+	PlayedMatches int    `json:"playedMatches"`
+	Goals         int    `json:"goals"`
+	Assists       *int   `json:"assists"`
+	Penalties     *int   `json:"penalties"`
+}
+
+// ScorersResponse contains the top scorers for a competition season
+type ScorersResponse struct {
+	Count       int         `json:"count"`
+	Competition Competition `json:"competition"`
+	Season      Season      `json:"season"`
+	Scorers     []Scorer    `json:"scorers"`
+}
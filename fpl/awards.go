@@ -0,0 +1,189 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrGameweek is returned for a gameweek that has not started
+var ErrGameweek = errors.New("gameweek has not started")
+
+type GameweekEntry struct { // a manager's gameweek from their picks, the basis of the awards
+	ManagerEntry
+	PointsOnBench     int `json:"points_on_bench"`
+	CaptainPoints     int `json:"captain_points"`      // before the captain's multiplier
+	BestCaptainPoints int `json:"best_captain_points"` // the most points of a player who scored for the manager
+}
+type AwardWinner struct { // a manager who won an award, level winners share it
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Team string `json:"team"`
+}
+type Award struct { // a weekly award with the winning value
+	Award   string        `json:"award"`
+	Value   int           `json:"value"`
+	Winners []AwardWinner `json:"winners"`
+}
+type AwardsResponse struct { // response with the awards for a gameweek
+	LeagueName string         `json:"league_name,omitempty"`
+	Gameweek   int            `json:"gameweek"`
+	Timestamp  string         `json:"timestamp"`
+	Status     string         `json:"status"`
+	Awards     []Award        `json:"awards"`
+	Errors     []ManagerError `json:"errors"`
+}
+
+// Awards writes the awards for the gameweek in query parameter "gw", or the current gameweek, as json
+func Awards(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	gameweek, ok := requestGameweek(w, r)
+	if !ok {
+		return
+	}
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	awardsResponse, err := getAwards(r.Context(), managerList, gameweek)
+	if errors.Is(err, ErrGameweek) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	awardsResponse.LeagueName = leagueName
+
+	writeJSON(w, awardsResponse)
+}
+
+// getAwards gets the managers' picks for the gameweek, 0 for the current gameweek, and gives the awards.
+// Managers that could not be retrieved are listed in the errors.
+func getAwards(ctx context.Context, managerList []string, gameweek int) (AwardsResponse, error) {
+	gameweekPicks, err := getLeaguePicks(ctx, managerList, gameweek)
+	if err != nil {
+		return AwardsResponse{}, err
+	}
+
+	entries := make([]GameweekEntry, 0, len(gameweekPicks.League))
+	for _, entry := range gameweekPicks.League {
+		entries = append(entries, gameweekEntry(entry, gameweekPicks.Picks[entry.ID], gameweekPicks.Live, gameweekPicks.Gameweek))
+	}
+
+	return AwardsResponse{
+		Gameweek:  gameweekPicks.Gameweek,
+		Timestamp: time.Now().Format(timestampLayout),
+		Status:    gameweekPicks.Status,
+		Awards:    giveAwards(entries, getTieBreaks()),
+		Errors:    gameweekPicks.Errors,
+	}, nil
+}
+
+// gameweekEntry scores the manager's picks for the gameweek, the league rank is set by giveAwards
+func gameweekEntry(entry ManagerEntry, picks PicksResponse, live map[int]ElementStats, gameweek int) GameweekEntry {
+	history := picks.EntryHistory
+	gameweekEntry := GameweekEntry{
+		ManagerEntry: ManagerEntry{
+			ID:              entry.ID,
+			Name:            entry.Name,
+			Team:            entry.Team,
+			Points:          history.TotalPoints,
+			Rank:            history.OverallRank,
+			GwPoints:        history.Points,
+			GwTransfersCost: history.EventTransfersCost,
+			Link:            fmt.Sprintf("https://fantasy.premierleague.com/entry/%v/event/%d", entry.ID, gameweek),
+		},
+		PointsOnBench: history.PointsOnBench,
+	}
+
+	// the captain is whoever scored with the armband, the best captain is from the players who scored
+	for _, pick := range scorePicks(picks, live).Picks {
+		if pick.Multiplier > 1 {
+			gameweekEntry.CaptainPoints = pick.Points
+		}
+
+		if pick.Multiplier > 0 {
+			gameweekEntry.BestCaptainPoints = max(gameweekEntry.BestCaptainPoints, pick.Points)
+		}
+	}
+
+	return gameweekEntry
+}
+
+// giveAwards ranks the league on the gameweek's totals and gives the awards. Movers and transfer hits
+// are only awarded when somebody moved or took a hit.
+func giveAwards(entries []GameweekEntry, order []tieBreak) []Award {
+	league := make([]ManagerEntry, len(entries))
+	for i, entry := range entries {
+		league[i] = entry.ManagerEntry
+	}
+
+	rankLeague(league, order)
+
+	ranked := make(map[int]ManagerEntry, len(league))
+	for _, entry := range league {
+		ranked[entry.ID] = entry
+	}
+
+	for i := range entries {
+		entries[i].ManagerEntry = ranked[entries[i].ID]
+	}
+
+	climb := func(e GameweekEntry) int { return e.PreviousLeagueRank - e.LeagueRank }
+	captaincy := func(e GameweekEntry) int { return e.CaptainPoints - e.BestCaptainPoints }
+
+	awards := []Award{
+		award("Manager of the week", entries, func(e GameweekEntry) int { return e.GwPoints }, false),
+		award("Lowest score", entries, func(e GameweekEntry) int { return e.GwPoints }, true),
+	}
+
+	if climber := award("Biggest climber", entries, climb, false); climber.Value > 0 {
+		awards = append(awards, climber)
+	}
+
+	if faller := award("Biggest faller", entries, climb, true); faller.Value < 0 {
+		awards = append(awards, faller)
+	}
+
+	awards = append(awards,
+		award("Best captain", entries, captaincy, false),
+		award("Worst captain", entries, captaincy, true),
+		award("Most points on the bench", entries, func(e GameweekEntry) int { return e.PointsOnBench }, false),
+	)
+
+	if hits := award("Most expensive transfer hits", entries, func(e GameweekEntry) int { return e.GwTransfersCost }, false); hits.Value > 0 {
+		awards = append(awards, hits)
+	}
+
+	return awards
+}
+
+// award goes to the managers with the highest value, or the lowest when lowest is set
+func award(title string, entries []GameweekEntry, value func(GameweekEntry) int, lowest bool) Award {
+	result := Award{Award: title, Winners: []AwardWinner{}}
+
+	for i, entry := range entries {
+		v := value(entry)
+
+		switch {
+		case i == 0 || (!lowest && v > result.Value) || (lowest && v < result.Value):
+			result.Value = v
+			result.Winners = []AwardWinner{{ID: entry.ID, Name: entry.Name, Team: entry.Team}}
+		case v == result.Value:
+			result.Winners = append(result.Winners, AwardWinner{ID: entry.ID, Name: entry.Name, Team: entry.Team})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,134 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func winners(award Award) []int {
+	var ids []int
+	for _, winner := range award.Winners {
+		ids = append(ids, winner.ID)
+	}
+
+	return ids
+}
+
+func TestGiveAwards(t *testing.T) {
+	// last gameweek's totals were 44, 60 and 68 so manager 1 climbs from third to first
+	entries := []GameweekEntry{
+		{ManagerEntry: ManagerEntry{ID: 1, Points: 100, GwPoints: 60, GwTransfersCost: 4}, PointsOnBench: 10, CaptainPoints: 5, BestCaptainPoints: 13},
+		{ManagerEntry: ManagerEntry{ID: 2, Points: 90, GwPoints: 30}, PointsOnBench: 2, CaptainPoints: 13, BestCaptainPoints: 13},
+		{ManagerEntry: ManagerEntry{ID: 3, Points: 80, GwPoints: 20, GwTransfersCost: 8}, PointsOnBench: 10, CaptainPoints: 2, BestCaptainPoints: 10},
+	}
+
+	type result struct {
+		Value   int
+		Winners []int
+	}
+
+	want := map[string]result{
+		"Manager of the week":          {60, []int{1}},
+		"Lowest score":                 {20, []int{3}},
+		"Biggest climber":              {2, []int{1}},
+		"Biggest faller":               {-2, []int{3}},
+		"Best captain":                 {0, []int{2}},
+		"Worst captain":                {-8, []int{1, 3}},
+		"Most points on the bench":     {10, []int{1, 3}},
+		"Most expensive transfer hits": {8, []int{3}},
+	}
+
+	awards := giveAwards(entries, nil)
+	if len(awards) != len(want) {
+		t.Errorf("giveAwards() = %d awards, want %d", len(awards), len(want))
+	}
+
+	for _, award := range awards {
+		if got := (result{award.Value, winners(award)}); !reflect.DeepEqual(got, want[award.Award]) {
+			t.Errorf("giveAwards() %s = %v, want %v", award.Award, got, want[award.Award])
+		}
+	}
+}
+
+func TestGiveAwardsQuietWeek(t *testing.T) {
+	// nobody moved or took a hit
+	entries := []GameweekEntry{
+		{ManagerEntry: ManagerEntry{ID: 1, Points: 100, GwPoints: 50}},
+		{ManagerEntry: ManagerEntry{ID: 2, Points: 90, GwPoints: 45}},
+	}
+
+	for _, award := range giveAwards(entries, nil) {
+		switch award.Award {
+		case "Biggest climber", "Biggest faller", "Most expensive transfer hits":
+			t.Errorf("giveAwards() gave %s = %v, want no award", award.Award, award)
+		}
+	}
+}
+
+func TestGetAwards(t *testing.T) {
+	live, err := os.ReadFile("testdata/live.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	picks, err := os.ReadFile("testdata/picks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entry/{id}/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"id": 1, "current_event": 9, "player_first_name": "first1", "player_last_name": "last1", "name": "team1"}`))
+	})
+	mux.HandleFunc("GET /entry/1/event/7/picks/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(picks)
+	})
+	mux.HandleFunc("GET /event/7/live/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(live)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	savedPicks, savedLive := fplPicksURL, fplLiveURL
+	t.Cleanup(func() { fplPicksURL, fplLiveURL = savedPicks, savedLive })
+
+	fplURL = ts.URL + "/entry/%v/"
+	fplPicksURL = ts.URL + "/entry/%v/event/%d/picks/"
+	fplLiveURL = ts.URL + "/event/%d/live/"
+	resetCaches(t)
+
+	mockFixtures(t)
+
+	testResponse, err := getAwards(context.Background(), []string{"1"}, 7)
+	if err != nil {
+		t.Fatalf(`getAwards("1", 7) err = (%v), want: nil err`, err)
+	}
+
+	// captain 10 scored 13, the most of any player
+	for _, award := range testResponse.Awards {
+		switch award.Award {
+		case "Manager of the week":
+			if award.Value != 40 || award.Winners[0].Name != "first1 last1" {
+				t.Errorf("getAwards %s = %+v, want first1 last1 with 40", award.Award, award)
+			}
+		case "Best captain":
+			if award.Value != 0 {
+				t.Errorf("getAwards %s = %+v, want 0 points missed", award.Award, award)
+			}
+		case "Most expensive transfer hits":
+			if award.Value != 4 {
+				t.Errorf("getAwards %s = %+v, want 4", award.Award, award)
+			}
+		}
+	}
+
+	if _, err := getAwards(context.Background(), []string{"1"}, 10); !errors.Is(err, ErrGameweek) {
+		t.Errorf(`getAwards("1", 10) err = (%v), want: ErrGameweek`, err)
+	}
+}
@@ -0,0 +1,64 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type BootstrapResponse struct { // fields retrieved from FPL bootstrap-static API
+	Events       []Event    `json:"events"`
+	Phases       []Phase    `json:"phases"`
+	Elements     []Element  `json:"elements"`
+	Teams        []Club     `json:"teams"`
+	ElementTypes []Position `json:"element_types"`
+}
+type Event struct { // a gameweek
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	DeadlineTime time.Time `json:"deadline_time"`
+	Finished     bool      `json:"finished"`
+	DataChecked  bool      `json:"data_checked"` // bonus points are confirmed
+	IsCurrent    bool      `json:"is_current"`
+	IsNext       bool      `json:"is_next"`
+}
+type Phase struct { // a run of gameweeks, the overall season or a month
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	StartEvent int    `json:"start_event"`
+	StopEvent  int    `json:"stop_event"`
+}
+
+type Element struct { // a player
+	ID          int    `json:"id"`
+	FirstName   string `json:"first_name"`
+	SecondName  string `json:"second_name"`
+	WebName     string `json:"web_name"`
+	Team        int    `json:"team"`
+	ElementType int    `json:"element_type"`
+	NowCost     int    `json:"now_cost"` // tenths of a million
+}
+type Club struct { // a Premier League team
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+}
+type Position struct { // an element type: goalkeeper, defender, midfielder or forward
+	ID                int    `json:"id"`
+	SingularName      string `json:"singular_name"`
+	SingularNameShort string `json:"singular_name_short"`
+}
+
+// the phase covering the whole season, the others are months
+const overallPhase = 1
+
+var fplBootstrapURL = "https://fantasy.premierleague.com/api/bootstrap-static/"
+
+func getBootstrap(ctx context.Context) (BootstrapResponse, error) {
+	var fplResponse BootstrapResponse
+	if err := getJSON(ctx, fplBootstrapURL, &fplResponse); err != nil {
+		return BootstrapResponse{}, fmt.Errorf("get bootstrap-static %w", err)
+	}
+
+	return fplResponse, nil
+}
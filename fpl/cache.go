@@ -0,0 +1,131 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type cachedValue[V any] struct {
+	value   V
+	fetched time.Time
+	expires time.Time
+}
+
+// a load in progress, shared by concurrent callers
+type cacheCall[V any] struct {
+	done    chan struct{} // closed when the load finishes
+	value   V
+	fetched time.Time
+	err     error
+}
+
+// A Cache serves values loaded from the FPL API, reused for the ttl when each was loaded.
+// Concurrent loads of a key are shared and errors are not cached.
+type Cache[K comparable, V any] struct {
+	load func(ctx context.Context, key K) (V, error)
+	ttl  func(ctx context.Context, now time.Time) time.Duration
+	now  func() time.Time
+
+	mu     sync.Mutex
+	values map[K]cachedValue[V]
+	calls  map[K]*cacheCall[V]
+}
+
+// NewCache returns a Cache that gets values with load and keeps each for the ttl when it was loaded
+func NewCache[K comparable, V any](load func(ctx context.Context, key K) (V, error),
+	ttl func(ctx context.Context, now time.Time) time.Duration,
+) *Cache[K, V] {
+	return &Cache[K, V]{
+		load:   load,
+		ttl:    ttl,
+		now:    time.Now,
+		values: map[K]cachedValue[V]{},
+		calls:  map[K]*cacheCall[V]{},
+	}
+}
+
+// get returns the value for key and when it was loaded. The load is made with the first caller's ctx,
+// other callers load again if it was cancelled.
+func (c *Cache[K, V]) get(ctx context.Context, key K) (V, time.Time, error) {
+	var zero V
+
+	for {
+		c.mu.Lock()
+
+		if cached, ok := c.values[key]; ok && c.now().Before(cached.expires) {
+			c.mu.Unlock()
+			return cached.value, cached.fetched, nil
+		}
+
+		if call, ok := c.calls[key]; ok {
+			c.mu.Unlock()
+
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return zero, time.Time{}, ctx.Err()
+			}
+
+			if isContextErr(call.err) && ctx.Err() == nil {
+				continue
+			}
+
+			return call.value, call.fetched, call.err
+		}
+
+		call := &cacheCall[V]{done: make(chan struct{})}
+		c.calls[key] = call
+		c.mu.Unlock()
+
+		return c.fetch(ctx, key, call)
+	}
+}
+
+func (c *Cache[K, V]) fetch(ctx context.Context, key K, call *cacheCall[V]) (V, time.Time, error) {
+	defer close(call.done)
+
+	call.value, call.err = c.load(ctx, key)
+
+	fetched := c.now()
+	call.fetched = fetched
+
+	var ttl time.Duration
+	if call.err == nil {
+		ttl = c.ttl(ctx, fetched)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.calls, key)
+
+	if call.err != nil {
+		var zero V
+		return zero, time.Time{}, call.err
+	}
+
+	// expired values are dropped as new ones are stored
+	for key, cached := range c.values {
+		if !fetched.Before(cached.expires) {
+			delete(c.values, key)
+		}
+	}
+
+	c.values[key] = cachedValue[V]{value: call.value, fetched: fetched, expires: fetched.Add(ttl)}
+
+	return call.value, fetched, nil
+}
+
+// reset drops every cached value, loads in progress are still shared
+func (c *Cache[K, V]) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.values)
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
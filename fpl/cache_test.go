@@ -0,0 +1,181 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resetCaches drops the cached entries, chips, triple captain points, element points and leagues before and after a test that serves its own
+func resetCaches(t *testing.T) {
+	reset := func() {
+		entryResponses.reset()
+		managerChips.reset()
+		tripleCaptainYields.reset()
+		elementRounds.reset()
+		classicLeagues.reset()
+	}
+
+	reset()
+	t.Cleanup(reset)
+}
+
+// testCache returns a cache counting its loads, with a clock the test moves on
+func testCache(load func(ctx context.Context, entry string) (Response, error)) (*Cache[string, Response], *atomic.Int32, *time.Time) {
+	var loads atomic.Int32
+
+	now := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+
+	cache := NewCache(func(ctx context.Context, entry string) (Response, error) {
+		loads.Add(1)
+		return load(ctx, entry)
+	}, func(context.Context, time.Time) time.Duration { return liveEntryTTL })
+	cache.now = func() time.Time { return now }
+
+	return cache, &loads, &now
+}
+
+func TestCacheReuse(t *testing.T) {
+	cache, loads, now := testCache(func(context.Context, string) (Response, error) {
+		return Response{ID: 1}, nil
+	})
+	ctx := context.Background()
+	first := *now
+
+	if response, fetched, err := cache.get(ctx, "1"); err != nil || response.ID != 1 || !fetched.Equal(first) {
+		t.Fatalf("get() = %+v, %v, (%v), want entry 1 fetched now", response, fetched, err)
+	}
+
+	*now = now.Add(liveEntryTTL - time.Second)
+
+	if _, fetched, _ := cache.get(ctx, "1"); loads.Load() != 1 || !fetched.Equal(first) {
+		t.Errorf("get() before expiry loads = %d fetched %v, want 1 load fetched %v", loads.Load(), fetched, first)
+	}
+
+	*now = now.Add(time.Second)
+
+	if _, fetched, _ := cache.get(ctx, "1"); loads.Load() != 2 || !fetched.Equal(*now) {
+		t.Errorf("get() after expiry loads = %d fetched %v, want 2 loads fetched %v", loads.Load(), fetched, *now)
+	}
+
+	cache.reset()
+
+	if _, _, _ = cache.get(ctx, "1"); loads.Load() != 3 {
+		t.Errorf("get() after reset loads = %d, want: 3", loads.Load())
+	}
+}
+
+func TestCacheErrorsNotCached(t *testing.T) {
+	errDown := errors.New("down")
+	cache, loads, _ := testCache(func(context.Context, string) (Response, error) {
+		return Response{}, errDown
+	})
+
+	for range 2 {
+		if _, _, err := cache.get(context.Background(), "1"); !errors.Is(err, errDown) {
+			t.Errorf("get() err = (%v), want: %v", err, errDown)
+		}
+	}
+
+	if loads.Load() != 2 {
+		t.Errorf("loads = %d, want: 2", loads.Load())
+	}
+}
+
+func TestCacheSingleFlight(t *testing.T) {
+	release := make(chan struct{})
+	cache, loads, _ := testCache(func(context.Context, string) (Response, error) {
+		<-release
+		return Response{ID: 1}, nil
+	})
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+
+	responses := make([]Response, callers)
+	for i := range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			responses[i], _, _ = cache.get(context.Background(), "1")
+		}()
+	}
+
+	// wait for the callers to share the fetch
+	for {
+		cache.mu.Lock()
+		started := len(cache.calls) == 1
+		cache.mu.Unlock()
+
+		if started {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("loads = %d, want: 1", loads.Load())
+	}
+
+	for i, response := range responses {
+		if response.ID != 1 {
+			t.Errorf("caller %d response = %+v, want entry 1", i, response)
+		}
+	}
+}
+
+func TestCacheLeaderCancelled(t *testing.T) {
+	started := make(chan struct{})
+
+	var first atomic.Bool
+
+	cache, loads, _ := testCache(func(ctx context.Context, _ string) (Response, error) {
+		if first.CompareAndSwap(false, true) {
+			close(started)
+			<-ctx.Done()
+
+			return Response{}, ctx.Err()
+		}
+
+		return Response{ID: 1}, nil
+	})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+
+	go func() {
+		_, _, err := cache.get(leaderCtx, "1")
+		leaderErr <- err
+	}()
+
+	<-started
+
+	follower := make(chan Response)
+
+	go func() {
+		response, _, _ := cache.get(context.Background(), "1")
+		follower <- response
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader get() err = (%v), want: context.Canceled", err)
+	}
+
+	if response := <-follower; response.ID != 1 || loads.Load() != 2 {
+		t.Errorf("follower get() = %+v after %d loads, want entry 1 after 2", response, loads.Load())
+	}
+}
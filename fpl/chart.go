@@ -0,0 +1,239 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// chart layout in pixels
+const (
+	chartWidth       = 800
+	chartPanelHeight = 240
+	chartLeft        = 70  // space for the rank labels
+	chartRight       = 170 // space for the legend
+	chartTop         = 50
+	chartPanelGap    = 70
+	chartPlotWidth   = chartWidth - chartLeft - chartRight
+)
+
+// line colours, repeated for big leagues
+var chartColours = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+type chartTick struct {
+	Position float64
+	Label    string
+}
+type chartLine struct {
+	Name   string
+	Colour string
+	Points string // x,y pairs for a polyline
+}
+type chartLegend struct {
+	Name   string
+	Colour string
+	Y      float64
+}
+type chartPanel struct {
+	Title  string
+	Top    float64
+	YTicks []chartTick
+	Lines  []chartLine
+}
+type chartData struct {
+	LeagueName  string
+	Width       int
+	Height      int
+	Left        float64
+	Right       float64 // where the plots end
+	PanelHeight float64
+	XTicks      []chartTick
+	Panels      []chartPanel
+	Legend      []chartLegend
+}
+
+// Chart displays the league's rank trajectory, league rank and overall rank at the end of each finished
+// gameweek, as svg. Gameweeks missing from the snapshots are backfilled from the managers' history.
+func Chart(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	chart, err := getChart(r.Context(), managerList)
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	chart.LeagueName = leagueName
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+
+	chartTemplate := template.Must(template.ParseFiles("fpl/ChartTemplate.svg"))
+	if err := chartTemplate.Execute(w, chart); err != nil {
+		log.Printf("error executing chartTemplate: %v", err)
+	}
+}
+
+// getChart backfills the managers' snapshots for the finished gameweeks and plots them
+func getChart(ctx context.Context, managerList []string) (chartData, error) {
+	bootstrap, err := reference.Bootstrap(ctx)
+	if err != nil {
+		return chartData{}, err
+	}
+
+	finished := []int{}
+
+	for _, event := range bootstrap.Events {
+		if event.Finished && event.DataChecked {
+			finished = append(finished, event.ID)
+		}
+	}
+
+	if err := backfillSnapshots(ctx, managerList, finished); err != nil {
+		return chartData{}, err
+	}
+
+	stored := map[string][]Snapshot{}
+
+	for _, manager := range managerList {
+		if stored[manager], err = snapshots.Load(manager); err != nil {
+			return chartData{}, err
+		}
+	}
+
+	return plotChart(managerList, stored), nil
+}
+
+// plotChart ranks the league at every gameweek with snapshots and draws a line for each manager in
+// a league rank panel and an overall rank panel with a log scale
+func plotChart(managerList []string, stored map[string][]Snapshot) chartData {
+	// the league at each gameweek
+	gameweeks := map[int][]ManagerEntry{}
+	lastGameweek := 1
+
+	for _, manager := range managerList {
+		for _, snapshot := range stored[manager] {
+			gameweeks[snapshot.Gameweek] = append(gameweeks[snapshot.Gameweek], snapshot.ManagerEntry)
+			lastGameweek = max(lastGameweek, snapshot.Gameweek)
+		}
+	}
+
+	leagueRanks := map[int]map[int]int{} // gameweek, manager id, league rank
+	bestRank, worstRank := math.MaxInt, 1
+
+	for gameweek, league := range gameweeks {
+		rankLeague(league, getTieBreaks())
+
+		leagueRanks[gameweek] = map[int]int{}
+		for _, entry := range league {
+			leagueRanks[gameweek][entry.ID] = entry.LeagueRank
+
+			if entry.Rank > 0 {
+				bestRank, worstRank = min(bestRank, entry.Rank), max(worstRank, entry.Rank)
+			}
+		}
+	}
+
+	bestRank = min(bestRank, worstRank)
+
+	x := func(gameweek int) float64 {
+		if lastGameweek == 1 {
+			return chartLeft + chartPlotWidth/2
+		}
+
+		return chartLeft + float64(gameweek-1)*chartPlotWidth/float64(lastGameweek-1)
+	}
+
+	// league rank 1 at the top to the size of the league at the bottom
+	leagueSize := max(len(managerList), 2)
+	leagueY := func(rank int) float64 {
+		return float64(rank-1) * chartPanelHeight / float64(leagueSize-1)
+	}
+
+	// overall rank between the powers of ten around the best and worst ranks
+	low := math.Floor(math.Log10(float64(bestRank)))
+	high := max(math.Ceil(math.Log10(float64(worstRank))), low+1)
+	overallY := func(rank int) float64 {
+		return (math.Log10(float64(rank)) - low) * chartPanelHeight / (high - low)
+	}
+
+	chart := chartData{
+		Width:       chartWidth,
+		Height:      chartTop + 2*chartPanelHeight + chartPanelGap + 40,
+		Left:        chartLeft,
+		Right:       chartLeft + chartPlotWidth,
+		PanelHeight: chartPanelHeight,
+		Panels: []chartPanel{
+			{Title: "League rank", Top: chartTop},
+			{Title: "Overall rank", Top: chartTop + chartPanelHeight + chartPanelGap},
+		},
+	}
+
+	for gameweek := 1; gameweek <= lastGameweek; gameweek++ {
+		if lastGameweek <= 19 || gameweek%2 == 1 {
+			chart.XTicks = append(chart.XTicks, chartTick{Position: roundPixel(x(gameweek)), Label: strconv.Itoa(gameweek)})
+		}
+	}
+
+	for rank := 1; rank <= leagueSize; rank++ {
+		chart.Panels[0].YTicks = append(chart.Panels[0].YTicks, chartTick{Position: roundPixel(leagueY(rank)), Label: strconv.Itoa(rank)})
+	}
+
+	for power := low; power <= high; power++ {
+		rank := int(math.Pow(10, power))
+		chart.Panels[1].YTicks = append(chart.Panels[1].YTicks, chartTick{Position: roundPixel(overallY(rank)), Label: shortRank(rank)})
+	}
+
+	for i, manager := range managerList {
+		managerSnapshots := stored[manager]
+		if len(managerSnapshots) == 0 {
+			continue
+		}
+
+		var league, overall []string
+
+		for _, snapshot := range managerSnapshots {
+			league = append(league, fmt.Sprintf("%.1f,%.1f", x(snapshot.Gameweek), leagueY(leagueRanks[snapshot.Gameweek][snapshot.ID])))
+
+			if snapshot.Rank > 0 {
+				overall = append(overall, fmt.Sprintf("%.1f,%.1f", x(snapshot.Gameweek), overallY(snapshot.Rank)))
+			}
+		}
+
+		latest := managerSnapshots[len(managerSnapshots)-1]
+		colour := chartColours[i%len(chartColours)]
+
+		chart.Panels[0].Lines = append(chart.Panels[0].Lines, chartLine{Name: latest.Team, Colour: colour, Points: strings.Join(league, " ")})
+		chart.Panels[1].Lines = append(chart.Panels[1].Lines, chartLine{Name: latest.Team, Colour: colour, Points: strings.Join(overall, " ")})
+		chart.Legend = append(chart.Legend, chartLegend{Name: latest.Team, Colour: colour, Y: float64(chartTop + 20*len(chart.Legend))})
+	}
+
+	return chart
+}
+
+func roundPixel(position float64) float64 {
+	return math.Round(position*10) / 10
+}
+
+// 1, 10, 100, 1K, 10K, 100K, 1M, 10M
+func shortRank(rank int) string {
+	switch {
+	case rank >= 1_000_000:
+		return strconv.Itoa(rank/1_000_000) + "M"
+	case rank >= 1_000:
+		return strconv.Itoa(rank/1_000) + "K"
+	default:
+		return strconv.Itoa(rank)
+	}
+}
@@ -0,0 +1,84 @@
+package fpl
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestPlotChart(t *testing.T) {
+	stored := map[string][]Snapshot{
+		"1": {
+			{Gameweek: 1, ManagerEntry: ManagerEntry{ID: 1, Team: "team<1>", Points: 60, GwPoints: 60, Rank: 1000}},
+			{Gameweek: 2, ManagerEntry: ManagerEntry{ID: 1, Team: "team<1>", Points: 100, GwPoints: 40, Rank: 10000}},
+		},
+		"2": {
+			{Gameweek: 1, ManagerEntry: ManagerEntry{ID: 2, Team: "team2", Points: 50, GwPoints: 50, Rank: 100000}},
+			{Gameweek: 2, ManagerEntry: ManagerEntry{ID: 2, Team: "team2", Points: 110, GwPoints: 60, Rank: 10}},
+		},
+	}
+
+	chart := plotChart([]string{"1", "2"}, stored)
+
+	// gameweeks 1 and 2 are the ends of the plot, league ranks 1 and 2 the top and bottom,
+	// overall ranks from 10 to 100K on a log scale
+	want := [][]string{
+		{"70.0,0.0 630.0,240.0", "70.0,240.0 630.0,0.0"},
+		{"70.0,120.0 630.0,180.0", "70.0,240.0 630.0,0.0"},
+	}
+
+	for p, panel := range chart.Panels {
+		for l, line := range panel.Lines {
+			if line.Points != want[p][l] {
+				t.Errorf("%v line %v = %q, want: %q", panel.Title, line.Name, line.Points, want[p][l])
+			}
+		}
+	}
+
+	var labels []string
+	for _, tick := range chart.Panels[1].YTicks {
+		labels = append(labels, tick.Label)
+	}
+
+	if strings.Join(labels, " ") != "10 100 1K 10K 100K" {
+		t.Errorf("overall rank labels = %v, want: 10 100 1K 10K 100K", labels)
+	}
+
+	chartTemplate := template.Must(template.ParseFiles("ChartTemplate.svg"))
+
+	var svg strings.Builder
+	if err := chartTemplate.Execute(&svg, chart); err != nil {
+		t.Fatalf("Execute() err = (%v), want: nil err", err)
+	}
+
+	for _, want := range []string{
+		`<polyline points="70.0,0.0 630.0,240.0"`,
+		`<text x="46" y="4">team&lt;1&gt;</text>`,
+		"Overall rank",
+	} {
+		if !strings.Contains(svg.String(), want) {
+			t.Errorf("chart does not contain %q\n%s", want, svg.String())
+		}
+	}
+}
+
+func TestGetChart(t *testing.T) {
+	useSnapshotsDir(t)
+	mockHistoryServer(t)
+	fixtureReference(t)
+
+	// gameweeks 1 to 3 are finished, manager 1's history has 1 and 2, manager 2 has none
+	chart, err := getChart(context.Background(), []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("getChart() err = (%v), want: nil err", err)
+	}
+
+	if len(chart.Legend) != 1 || chart.Legend[0].Name != "team1" || strings.Count(chart.Panels[1].Lines[0].Points, ",") != 2 {
+		t.Errorf("getChart() legend %+v overall %+v, want: team1 over 2 gameweeks", chart.Legend, chart.Panels[1].Lines)
+	}
+
+	if stored, _ := snapshots.Load("1"); len(stored) != 2 {
+		t.Errorf("snapshots after getChart() = %+v, want: gameweeks 1 and 2 backfilled", stored)
+	}
+}
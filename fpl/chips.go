@@ -0,0 +1,214 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// chips that do not change how picks are scored
+const (
+	ChipWildcard = "wildcard"
+	ChipFreeHit  = "freehit"
+)
+
+// each chip can be played once in each half of the season, the first half ends after this gameweek
+const chipHalfwayGameweek = 19
+
+// triple captain points of a gameweek that is finished and checked no longer change, they are kept for a day
+const checkedChipTTL = 24 * time.Hour
+
+// longest the league table waits for chips not yet cached, managers still to be fetched are shown without them,
+// shortened in tests
+var chipsTimeout = 2 * time.Second
+
+// every chip in the order they are shown, with display names
+var (
+	chipOrder = []string{ChipWildcard, ChipFreeHit, ChipBenchBoost, ChipTripleCaptain}
+	chipNames = map[string]string{
+		ChipWildcard:      "Wildcard",
+		ChipFreeHit:       "Free Hit",
+		ChipBenchBoost:    "Bench Boost",
+		ChipTripleCaptain: "Triple Captain",
+	}
+)
+
+type ChipUsage struct { // a chip a manager has played and the points it yielded
+	Chip     string `json:"chip"`
+	Name     string `json:"name"`
+	Gameweek int    `json:"gameweek"`
+	Points   int    `json:"points"`
+}
+type ManagerChips struct { // the chips a manager has played this season and those left in this half
+	Used      []ChipUsage `json:"used"`
+	Available []string    `json:"available"`
+}
+
+// a manager's chips as of a gameweek
+type chipsKey struct {
+	entry    string
+	gameweek int
+}
+
+// the chips of each manager, reused for as long as manager entries are
+var managerChips = NewCache(func(ctx context.Context, key chipsKey) (ManagerChips, error) {
+	return getManagerChips(ctx, key.entry, key.gameweek)
+}, entryTTL)
+
+// the points of each triple captain played in a gameweek that is finished and checked, by manager and gameweek
+var tripleCaptainYields = NewCache(func(ctx context.Context, key chipsKey) (int, error) {
+	return tripleCaptainPoints(ctx, key.entry, key.gameweek)
+}, func(context.Context, time.Time) time.Duration { return checkedChipTTL })
+
+// chipName is the display name of a chip, or its id for a chip that is not known
+func chipName(chip string) string {
+	if name, ok := chipNames[chip]; ok {
+		return name
+	}
+
+	return chip
+}
+
+// addChips sets the chips of each manager in the league for its gameweek, waiting up to chipsTimeout.
+// A manager whose chips could not be retrieved in time is logged and shown without them.
+func addChips(ctx context.Context, leagueResponse LeagueResponse) {
+	ctx, cancel := context.WithTimeout(ctx, chipsTimeout)
+	defer cancel()
+
+	ids := make([]string, len(leagueResponse.League))
+	for i, entry := range leagueResponse.League {
+		ids[i] = strconv.Itoa(entry.ID)
+	}
+
+	var mu sync.Mutex
+
+	chips := map[string]ManagerChips{} // those retrieved in time, kept when the timeout skips the rest
+
+	_, err := fetchAll(ctx, ids, func(ctx context.Context, entry string) error {
+		usage, _, err := managerChips.get(ctx, chipsKey{entry, leagueResponse.Gameweek})
+		if err != nil {
+			log.Printf("manager %v: %v", entry, err)
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		chips[entry] = usage
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("chips: %v", err)
+	}
+
+	for i, id := range ids {
+		if usage, ok := chips[id]; ok {
+			leagueResponse.League[i].Chips = &usage
+		}
+	}
+}
+
+// getManagerChips gets the chips the manager has played from their history with the points each yielded,
+// the whole gameweek for a wildcard or free hit, the bench for a bench boost and the captain's extra points
+// for a triple captain
+func getManagerChips(ctx context.Context, entry string, gameweek int) (ManagerChips, error) {
+	var history HistoryResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplHistoryURL, entry), &history); err != nil {
+		return ManagerChips{}, fmt.Errorf("get history for manager ID %v %w", entry, err)
+	}
+
+	chips := ManagerChips{Used: []ChipUsage{}, Available: []string{}}
+	usedThisHalf := map[string]bool{}
+
+	for _, chip := range history.Chips {
+		usage := ChipUsage{Chip: chip.Name, Name: chipName(chip.Name), Gameweek: chip.Event}
+
+		for _, gw := range history.Current {
+			if gw.Event != chip.Event {
+				continue
+			}
+
+			switch chip.Name {
+			case ChipWildcard, ChipFreeHit:
+				usage.Points = gw.Points
+			case ChipBenchBoost:
+				usage.Points = gw.PointsOnBench
+			}
+		}
+
+		if chip.Name == ChipTripleCaptain {
+			points, err := chipPoints(ctx, entry, chip.Event)
+			if err != nil {
+				return ManagerChips{}, err
+			}
+
+			usage.Points = points
+		}
+
+		chips.Used = append(chips.Used, usage)
+
+		if (chip.Event <= chipHalfwayGameweek) == (gameweek <= chipHalfwayGameweek) {
+			usedThisHalf[chip.Name] = true
+		}
+	}
+
+	for _, chip := range chipOrder {
+		if !usedThisHalf[chip] {
+			chips.Available = append(chips.Available, chip)
+		}
+	}
+
+	return chips, nil
+}
+
+// chipPoints is the triple captain's points for the gameweek, from tripleCaptainYields once the gameweek is checked
+func chipPoints(ctx context.Context, entry string, gameweek int) (int, error) {
+	if !gameweekChecked(ctx, gameweek) {
+		return tripleCaptainPoints(ctx, entry, gameweek)
+	}
+
+	points, _, err := tripleCaptainYields.get(ctx, chipsKey{entry, gameweek})
+
+	return points, err
+}
+
+// gameweekChecked is whether the gameweek is finished and its bonus points checked in bootstrap-static
+func gameweekChecked(ctx context.Context, gameweek int) bool {
+	bootstrap, err := reference.Bootstrap(ctx)
+	if err != nil {
+		return false
+	}
+
+	for _, event := range bootstrap.Events {
+		if event.ID == gameweek {
+			return event.Finished && event.DataChecked
+		}
+	}
+
+	return false
+}
+
+// tripleCaptainPoints is the captain's points from the third multiple, the vice captain's if they took the armband
+func tripleCaptainPoints(ctx context.Context, entry string, gameweek int) (int, error) {
+	picks, err := getPicks(ctx, entry, gameweek)
+	if err != nil {
+		return 0, err
+	}
+
+	live, err := getLiveStats(ctx, gameweek)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pick := range scorePicks(picks, live).Picks {
+		if pick.Multiplier == 3 {
+			return pick.Points, nil
+		}
+	}
+
+	return 0, nil
+}
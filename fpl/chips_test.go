@@ -0,0 +1,220 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// manager 1 played a triple captain in gameweek 1, a wildcard in 2 and a bench boost in 3
+const chipsHistory = `{
+  "current": [
+    {"event": 1, "points": 78, "total_points": 78, "points_on_bench": 4},
+    {"event": 2, "points": 61, "total_points": 139, "points_on_bench": 9},
+    {"event": 3, "points": 70, "total_points": 209, "points_on_bench": 17}
+  ],
+  "chips": [
+    {"name": "3xc", "time": "2024-08-16T12:00:00Z", "event": 1},
+    {"name": "wildcard", "time": "2024-08-23T12:00:00Z", "event": 2},
+    {"name": "bboost", "time": "2024-08-30T12:00:00Z", "event": 3}
+  ],
+  "past": []
+}`
+
+// mockChipsServer serves manager 1's history, their triple captain picks and the live points of gameweek 1,
+// counting the history requests
+func mockChipsServer(t *testing.T) *atomic.Int32 {
+	t.Helper()
+
+	picks, err := os.ReadFile("testdata/picks_3xc.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	live, err := os.ReadFile("testdata/live.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	var historyRequests atomic.Int32
+
+	mux.HandleFunc("GET /entry/1/history/", func(w http.ResponseWriter, _ *http.Request) {
+		historyRequests.Add(1)
+		fmt.Fprint(w, chipsHistory)
+	})
+	mux.HandleFunc("GET /entry/1/event/1/picks/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(picks)
+	})
+	mux.HandleFunc("GET /event/1/live/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(live)
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	savedHistory, savedPicks, savedLive := fplHistoryURL, fplPicksURL, fplLiveURL
+	fplHistoryURL = ts.URL + "/entry/%v/history/"
+	fplPicksURL = ts.URL + "/entry/%v/event/%d/picks/"
+	fplLiveURL = ts.URL + "/event/%d/live/"
+	resetCaches(t)
+
+	t.Cleanup(func() { fplHistoryURL, fplPicksURL, fplLiveURL = savedHistory, savedPicks, savedLive })
+
+	mockFixtures(t)
+
+	return &historyRequests
+}
+
+func TestGetManagerChips(t *testing.T) {
+	mockChipsServer(t)
+
+	chips, err := getManagerChips(context.Background(), "1", 4)
+	if err != nil {
+		t.Fatalf("getManagerChips() err = (%v), want: nil err", err)
+	}
+
+	// Haaland scored 13 as triple captain
+	want := []ChipUsage{
+		{Chip: ChipTripleCaptain, Name: "Triple Captain", Gameweek: 1, Points: 13},
+		{Chip: ChipWildcard, Name: "Wildcard", Gameweek: 2, Points: 61},
+		{Chip: ChipBenchBoost, Name: "Bench Boost", Gameweek: 3, Points: 17},
+	}
+
+	if !slices.Equal(chips.Used, want) {
+		t.Errorf("getManagerChips() used = %+v, want: %+v", chips.Used, want)
+	}
+
+	if !slices.Equal(chips.Available, []string{ChipFreeHit}) {
+		t.Errorf("getManagerChips() available = %v, want: [freehit]", chips.Available)
+	}
+
+	// every chip is available again in the second half of the season
+	chips, err = getManagerChips(context.Background(), "1", chipHalfwayGameweek+1)
+	if err != nil || !slices.Equal(chips.Available, chipOrder) || len(chips.Used) != 3 {
+		t.Errorf("getManagerChips() second half = %+v, (%v), want: 3 used and all available", chips, err)
+	}
+}
+
+func TestGetManagerChipsTripleCaptainReused(t *testing.T) {
+	mockChipsServer(t)
+
+	picks := fplPicksURL
+
+	var picksRequests atomic.Int32
+
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		picksRequests.Add(1)
+		http.Redirect(w, r, fmt.Sprintf(picks, 1, 1), http.StatusFound)
+	}))
+	t.Cleanup(counting.Close)
+
+	fplPicksURL = counting.URL + "/entry/%v/event/%d/picks/"
+
+	// gameweek 1 is finished and checked, its triple captain is only scored once
+	for range 2 {
+		chips, err := getManagerChips(context.Background(), "1", 4)
+		if err != nil || len(chips.Used) != 3 || chips.Used[0].Points != 13 {
+			t.Errorf("getManagerChips() = %+v, (%v), want: triple captain with 13 points", chips, err)
+		}
+	}
+
+	if picksRequests.Load() != 1 {
+		t.Errorf("picks requests = %d, want: 1", picksRequests.Load())
+	}
+}
+
+func TestAddChips(t *testing.T) {
+	historyRequests := mockChipsServer(t)
+
+	// manager 2 has no history
+	leagueResponse := LeagueResponse{Gameweek: 4, League: []ManagerEntry{{ID: 1}, {ID: 2}}}
+	addChips(context.Background(), leagueResponse)
+
+	if chips := leagueResponse.League[0].Chips; chips == nil || len(chips.Used) != 3 {
+		t.Errorf("manager 1 chips = %+v, want: 3 used", chips)
+	}
+
+	if chips := leagueResponse.League[1].Chips; chips != nil {
+		t.Errorf("manager 2 chips = %+v, want: none", chips)
+	}
+
+	// manager 1's chips are reused for the gameweek
+	leagueResponse = LeagueResponse{Gameweek: 4, League: []ManagerEntry{{ID: 1}}}
+	addChips(context.Background(), leagueResponse)
+
+	if chips := leagueResponse.League[0].Chips; chips == nil || len(chips.Used) != 3 || historyRequests.Load() != 1 {
+		t.Errorf("manager 1 chips again = %+v after %d history requests, want: 3 used after 1", chips, historyRequests.Load())
+	}
+}
+
+func TestAddChipsTimeout(t *testing.T) {
+	mockChipsServer(t)
+
+	history := strings.Replace(fplHistoryURL, "%v", "1", 1)
+
+	// manager 2's history never arrives
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/entry/2/") {
+			<-r.Context().Done()
+			return
+		}
+
+		http.Redirect(w, r, history, http.StatusFound)
+	}))
+	t.Cleanup(slow.Close)
+
+	savedHistory, savedTimeout := fplHistoryURL, chipsTimeout
+	t.Cleanup(func() { fplHistoryURL, chipsTimeout = savedHistory, savedTimeout })
+
+	fplHistoryURL, chipsTimeout = slow.URL+"/entry/%v/history/", 50*time.Millisecond
+
+	leagueResponse := LeagueResponse{Gameweek: 4, League: []ManagerEntry{{ID: 1}, {ID: 2}}}
+	start := time.Now()
+	addChips(context.Background(), leagueResponse)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("addChips() took %v, want: about %v", elapsed, chipsTimeout)
+	}
+
+	if leagueResponse.League[0].Chips == nil || leagueResponse.League[1].Chips != nil {
+		t.Errorf("chips = %+v, %+v, want: manager 1's only", leagueResponse.League[0].Chips, leagueResponse.League[1].Chips)
+	}
+}
+
+func TestTableTemplateChips(t *testing.T) {
+	tableTemplate := template.Must(template.New("TableTemplate.html").Funcs(tableFuncs).ParseFiles("TableTemplate.html"))
+
+	leagueResponse := LeagueResponse{
+		Gameweek: 4,
+		League: []ManagerEntry{
+			{ID: 1, Team: "team1", Chips: &ManagerChips{
+				Used:      []ChipUsage{{Chip: ChipTripleCaptain, Name: "Triple Captain", Gameweek: 1, Points: 13}, {Chip: ChipWildcard, Name: "Wildcard", Gameweek: 2, Points: 61}},
+				Available: []string{ChipFreeHit, ChipBenchBoost},
+			}},
+			{ID: 2, Team: "team2"},
+		},
+	}
+
+	var page strings.Builder
+	if err := tableTemplate.Execute(&page, leagueResponse); err != nil {
+		t.Fatalf("Execute() err = (%v), want: nil err", err)
+	}
+
+	for _, want := range []string{
+		"<td>Triple Captain GW1 (13 pts)<br>Wildcard GW2 (61 pts)</td>",
+		"<td>Free Hit, Bench Boost</td>",
+	} {
+		if !strings.Contains(page.String(), want) {
+			t.Errorf("table page does not contain %q\n%s", want, page.String())
+		}
+	}
+}
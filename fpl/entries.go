@@ -0,0 +1,68 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	liveEntryTTL     = time.Minute   // entries change as the matches of a live gameweek are played
+	finishedEntryTTL = 6 * time.Hour // entries only change at the next deadline once the gameweek is finished
+)
+
+// the manager entries used by every handler, by manager ID
+var entryResponses = NewCache(loadEntry, entryTTL)
+
+// loadEntry gets the manager's entry with the transfer hits of the current gameweek from its picks,
+// none for a manager without picks for it
+func loadEntry(ctx context.Context, entry string) (Response, error) {
+	var fplResponse Response
+	if err := getJSON(ctx, fmt.Sprintf(fplURL, entry), &fplResponse); err != nil {
+		return Response{}, err
+	}
+
+	if fplResponse.CurrentEvent == 0 {
+		return fplResponse, nil
+	}
+
+	picks, err := getPicks(ctx, entry, fplResponse.CurrentEvent)
+
+	var statusErr *StatusError
+	if err != nil && !(errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound) {
+		return Response{}, err
+	}
+
+	fplResponse.EventTransfersCost = picks.EntryHistory.EventTransfersCost
+
+	return fplResponse, nil
+}
+
+// entryTTL is finishedEntryTTL, up to the next deadline, once the current gameweek is finished and its data
+// checked, otherwise liveEntryTTL
+func entryTTL(ctx context.Context, now time.Time) time.Duration {
+	current, err := reference.CurrentEvent(ctx)
+	if err != nil || !current.Finished || !current.DataChecked {
+		return liveEntryTTL
+	}
+
+	ttl := finishedEntryTTL
+
+	if next, err := reference.NextEvent(ctx); err == nil {
+		ttl = min(ttl, next.DeadlineTime.Sub(now))
+	}
+
+	return max(ttl, liveEntryTTL)
+}
+
+// setCacheAge writes the seconds since the oldest entry in the response was fetched from the FPL API
+func setCacheAge(w http.ResponseWriter, fetched time.Time) {
+	if fetched.IsZero() {
+		return
+	}
+
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(fetched).Seconds())))
+}
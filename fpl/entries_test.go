@@ -0,0 +1,86 @@
+package fpl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEntryTTL(t *testing.T) {
+	ref := fixtureReference(t)
+	ctx := context.Background()
+	now := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+
+	// gameweek 4 is live
+	if ttl := entryTTL(ctx, now); ttl != liveEntryTTL {
+		t.Errorf("entryTTL() live = %v, want: %v", ttl, liveEntryTTL)
+	}
+
+	bootstrap, err := ref.Bootstrap(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	finished := func(checked bool) {
+		events := append([]Event{}, bootstrap.Events...)
+		events[3].Finished = true
+		events[3].DataChecked = checked
+		finishedBootstrap := bootstrap
+		finishedBootstrap.Events = events
+		reference = NewReference(func(context.Context) (BootstrapResponse, error) { return finishedBootstrap, nil })
+	}
+
+	finished(false)
+
+	if ttl := entryTTL(ctx, now); ttl != liveEntryTTL {
+		t.Errorf("entryTTL() finished before data checked = %v, want: %v", ttl, liveEntryTTL)
+	}
+
+	finished(true)
+
+	if ttl := entryTTL(ctx, now); ttl != finishedEntryTTL {
+		t.Errorf("entryTTL() finished = %v, want: %v", ttl, finishedEntryTTL)
+	}
+
+	// gameweek 5 deadline is 2024-09-21T10:00Z
+	nearDeadline := time.Date(2024, 9, 21, 8, 0, 0, 0, time.UTC)
+	if ttl := entryTTL(ctx, nearDeadline); ttl != 2*time.Hour {
+		t.Errorf("entryTTL() two hours before the deadline = %v, want: 2h", ttl)
+	}
+}
+
+func TestPointsCacheAge(t *testing.T) {
+	t.Setenv("managers", "1,2")
+
+	var requests atomic.Int32
+
+	ts := setTestServer()
+	defer ts.Close()
+
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Redirect(w, r, ts.URL+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer counting.Close()
+
+	fplURL = counting.URL + EntryPlaceholder
+	resetCaches(t)
+
+	for i := range 2 {
+		rec := httptest.NewRecorder()
+		Points(rec, httptest.NewRequest(http.MethodGet, "/fpl", http.NoBody))
+
+		age, err := strconv.Atoi(rec.Header().Get("Age"))
+		if rec.Code != http.StatusOK || err != nil || age < 0 {
+			t.Errorf("Points() request %d = %d with Age %q, want 200 with an age in seconds", i+1, rec.Code, rec.Header().Get("Age"))
+		}
+	}
+
+	if requests.Load() != 2 {
+		t.Errorf("FPL API requests = %d, want: 2, one per manager", requests.Load())
+	}
+}
@@ -0,0 +1,166 @@
+package fpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	maxWorkers      = 8               // concurrent requests to the FPL API per incoming request
+	requestTimeout  = 5 * time.Second // per call, less than the server write timeout
+	responseTimeout = 8 * time.Second // every call for a response, retries included, less than the server write timeout
+	maxRetries      = 3               // retries of a call that failed while the game is updating or with a server error
+)
+
+// client for every FPL API call, the timeout is a backstop for calls made without a deadline
+var httpClient = &http.Client{Timeout: 2 * requestTimeout}
+
+// the first retry waits up to retryBackoff, doubling for each retry after
+var retryBackoff = 250 * time.Millisecond
+
+// ErrUpdating is returned while the FPL API is down between gameweeks, with a 503 or a "game is being updated" page
+var ErrUpdating = errors.New("the game is being updated")
+
+// A StatusError is returned for an FPL API response that was not OK
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("not OK, Status: %v", e.Status)
+}
+
+// withResponseTimeout returns the request with its context done after responseTimeout, so that FPL API calls
+// and their retries finish before the server's write timeout
+func withResponseTimeout(r *http.Request) (*http.Request, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), responseTimeout)
+
+	return r.WithContext(ctx), cancel
+}
+
+// getJSON requests url and unmarshals an OK response into v. Calls that fail while the game is updating,
+// or with a server error or rate limit, are retried up to maxRetries times with jittered exponential backoff
+// while the wait ends before ctx's deadline.
+func getJSON(ctx context.Context, url string, v any) error {
+	for retry := 0; ; retry++ {
+		err := getJSONOnce(ctx, url, v)
+		if err == nil || retry == maxRetries || !retryable(err) {
+			return err
+		}
+
+		// full jitter, a random wait up to the backoff
+		wait := rand.N(retryBackoff<<retry) + 1
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return errors.Is(err, ErrUpdating)
+}
+
+// getJSONOnce requests url, cancelled with ctx or after requestTimeout, and unmarshals an OK response into v
+func getJSONOnce(ctx context.Context, url string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return fmt.Errorf("%w: %w", ErrUpdating, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status})
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	if !json.Valid(body) && bytes.Contains(bytes.ToLower(body), []byte("game is being updated")) {
+		return ErrUpdating
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// fetchAll calls fetch for every manager using at most maxWorkers goroutines and returns the results
+// in managerList order. Every goroutine has finished when it returns. Once ctx's deadline has passed
+// the remaining managers are still passed to fetch, which fails them at once unless they are cached,
+// so the results fetched in time are kept. If ctx is cancelled, the client has gone, the remaining
+// managers are skipped and ctx's error is returned.
+func fetchAll[T any](ctx context.Context, managerList []string, fetch func(ctx context.Context, manager string) T) ([]T, error) {
+	results := make([]T, len(managerList))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(maxWorkers, len(managerList)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i] = fetch(ctx, managerList[i])
+			}
+		}()
+	}
+
+send:
+	for i := range managerList {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				break send
+			}
+
+			jobs <- i
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+
+	return results, nil
+}
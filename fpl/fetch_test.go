@@ -0,0 +1,345 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// checkNoLeaks waits for the goroutine count to drop back to baseline, idle client
+// connections are closed first so their read and write loops can exit
+func checkNoLeaks(t *testing.T, baseline int) {
+	t.Helper()
+
+	httpClient.CloseIdleConnections()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Errorf("goroutines = %d, want <= %d\n%s", runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func managerIDs(n int) []string {
+	managerList := make([]string, n)
+	for i := range managerList {
+		managerList[i] = strconv.Itoa(i + 1)
+	}
+
+	return managerList
+}
+
+func TestFetchAllBoundedParallelism(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintf(w, `{"id":%s,"current_event":%d}`, strings.Trim(r.URL.Path, "/"), Gameweek)
+	}))
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+	managerList := managerIDs(30)
+
+	results, err := fetchAll(context.Background(), managerList, getManagerEntries)
+	if err != nil {
+		t.Fatalf("fetchAll() err = (%v), want: nil err", err)
+	}
+
+	if maxInFlight.Load() > maxWorkers {
+		t.Errorf("fetchAll() max concurrent requests = %d, want <= %d", maxInFlight.Load(), maxWorkers)
+	}
+
+	// results in manager list order
+	for i, result := range results {
+		if result.Error != nil || result.ManagerEntryValue.ID != i+1 {
+			t.Errorf("fetchAll() result[%d] = %+v, want manager %d", i, result, i+1)
+		}
+	}
+}
+
+func TestGetDataNoLeakAfterErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+	baseline := runtime.NumGoroutine()
+
+	if _, err := getData(context.Background(), managerIDs(50)); err == nil {
+		t.Error("getData() err = nil, want: not OK error")
+	}
+
+	checkNoLeaks(t, baseline)
+}
+
+func TestGetDataCancelled(t *testing.T) {
+	var requests atomic.Int32
+
+	// server never answers, requests are only released when the client goes away
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+	baseline := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+
+	_, err := getData(ctx, managerIDs(50))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("getData() err = (%v), want: context.Canceled", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("getData() returned after %v, want prompt return on cancel", elapsed)
+	}
+
+	// only the first batch of managers was requested
+	if requests.Load() > maxWorkers {
+		t.Errorf("requests = %d, want <= %d", requests.Load(), maxWorkers)
+	}
+
+	checkNoLeaks(t, baseline)
+}
+
+func TestGetDataDeadline(t *testing.T) {
+	// manager 1 answers, the rest never do
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1" {
+			<-r.Context().Done()
+			return
+		}
+
+		fmt.Fprintf(w, `{"id":1,"current_event":%d}`, Gameweek)
+	}))
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+
+	baseline := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	leagueResponse, err := getData(ctx, managerIDs(20))
+	if err != nil {
+		t.Fatalf("getData() err = (%v), want: nil err", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("getData() returned after %v, want prompt return at the deadline", elapsed)
+	}
+
+	if len(leagueResponse.League) != 1 || leagueResponse.League[0].ID != 1 {
+		t.Errorf("getData() league = %+v, want: manager 1", leagueResponse.League)
+	}
+
+	if leagueResponse.Status != StatusDegraded || len(leagueResponse.Errors) != 19 {
+		t.Errorf("getData() status = %v with %d errors, want: degraded with 19", leagueResponse.Status, len(leagueResponse.Errors))
+	}
+
+	checkNoLeaks(t, baseline)
+}
+
+func TestGetJSONTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	// the caller's deadline is shorter than requestTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var v any
+	if err := getJSON(ctx, ts.URL, &v); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("getJSON() err = (%v), want: context.DeadlineExceeded", err)
+	}
+}
+
+func TestMain(m *testing.M) {
+	// retries are immediate in tests
+	retryBackoff = time.Millisecond
+
+	// tests that need bootstrap-static use fixtureReference, the rest treat the gameweek as live
+	reference = NewReference(func(context.Context) (BootstrapResponse, error) {
+		return BootstrapResponse{}, errors.New("no bootstrap-static in tests")
+	})
+
+	// nothing is found upstream unless a test serves it, for the chips of every league table
+	ts := httptest.NewServer(http.NotFoundHandler())
+	fplHistoryURL = ts.URL + "/entry/%v/history/"
+	fplPicksURL = ts.URL + "/entry/%v/event/%d/picks/"
+	fplLiveURL = ts.URL + "/event/%d/live/"
+	fplFixturesURL = ts.URL + "/fixtures/?event=%d"
+
+	code := m.Run()
+
+	ts.Close()
+	os.Exit(code)
+}
+
+func TestGetJSONRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter) // one per request, the last is repeated
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name: "unavailable then OK",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { fmt.Fprint(w, `{"id":1}`) },
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "updating page then OK",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { fmt.Fprint(w, "<html><body>The game is being updated.</body></html>") },
+				func(w http.ResponseWriter) { fmt.Fprint(w, `{"id":1}`) },
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "too many requests then OK",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+				func(w http.ResponseWriter) { fmt.Fprint(w, `{"id":1}`) },
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "updating throughout",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { fmt.Fprint(w, "The game is being updated.") },
+			},
+			wantErr:   ErrUpdating,
+			wantCalls: maxRetries + 1,
+		},
+		{
+			name: "not found is not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			},
+			wantErr:   &StatusError{StatusCode: http.StatusNotFound},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				n := int(calls.Add(1))
+				tt.responses[min(n, len(tt.responses))-1](w)
+			}))
+			defer ts.Close()
+
+			var v struct {
+				ID int `json:"id"`
+			}
+
+			err := getJSON(context.Background(), ts.URL, &v)
+
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil || v.ID != 1 {
+					t.Errorf("getJSON() = (%v, %v), want: id 1", v, err)
+				}
+			case *StatusError:
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != want.StatusCode {
+					t.Errorf("getJSON() err = (%v), want: status %d", err, want.StatusCode)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Errorf("getJSON() err = (%v), want: %v", err, want)
+				}
+			}
+
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("requests = %d, want: %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGetJSONRetryCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var v any
+	if err := getJSON(ctx, ts.URL, &v); !errors.Is(err, context.Canceled) {
+		t.Errorf("getJSON() err = (%v), want: context.Canceled", err)
+	}
+}
+
+func TestGetJSONRetryDeadline(t *testing.T) {
+	var calls atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	saved := retryBackoff
+	retryBackoff = 1000 * time.Hour
+	t.Cleanup(func() { retryBackoff = saved })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// no wait for a retry that would end after the deadline
+	var v any
+	if err := getJSON(ctx, ts.URL, &v); !errors.Is(err, ErrUpdating) || calls.Load() != 1 || ctx.Err() != nil {
+		t.Errorf("getJSON() err = (%v) after %d calls, ctx err = (%v), want: ErrUpdating after 1 call before the deadline",
+			err, calls.Load(), ctx.Err())
+	}
+}
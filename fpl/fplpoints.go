@@ -1,11 +1,13 @@
-// reads a list of comma separated FPL manager ids from environment variable "managers"
+// reads a list of comma separated FPL manager ids from environment variable "managers",
+// or discovers them from a classic league with query parameter "league",
 // and retrieves the current gameweek scores for the managers.
 package fpl
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -23,60 +25,90 @@ type Response struct { // fields retrieved from FPL API
 	SummaryOverallRank   int    `json:"summary_overall_rank"`
 	SummaryEventPoints   int    `json:"summary_event_points"`
 	SummaryEventRank     int    `json:"summary_event_rank"`
+
+	EventTransfersCost int `json:"-"` // from the current gameweek's picks
 }
 type ManagerEntry struct { // stats for a manager for current gameweek
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Team     string `json:"team"`
-	Points   int    `json:"points"`
-	Rank     int    `json:"rank"`
-	GwPoints int    `json:"gw_points"`
-	GwRank   int    `json:"gw_rank"`
-	Link     string `json:"link"`
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Team               string `json:"team"`
+	Points             int    `json:"points"`
+	Rank               int    `json:"rank"`
+	GwPoints           int    `json:"gw_points"`
+	GwRank             int    `json:"gw_rank"`
+	GwTransfersCost    int    `json:"gw_transfers_cost"` // points deducted for transfer hits, gw_points are before them
+	LeagueRank         int    `json:"league_rank"`
+	PreviousLeagueRank int    `json:"previous_league_rank"`
+	Link               string `json:"link"`
+
+	Chips *ManagerChips `json:"chips,omitempty"` // on the league table only
 }
 type ManagerEntryResult struct { // result wrapper for ManagerEntry, Gameweek, Error
+	ManagerID         string
 	Gameweek          int
 	ManagerEntryValue ManagerEntry
+	Fetched           time.Time // when the entry was fetched from the FPL API
 	Error             error
 }
+type ManagerError struct { // a manager whose entry could not be retrieved
+	ID     string `json:"id"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error"`
+}
 type LeagueResponse struct { // response with array of manager entries
-	Gameweek  int            `json:"gameweek"`
-	Timestamp string         `json:"timestamp"`
-	League    []ManagerEntry `json:"league"`
+	LeagueName string         `json:"league_name,omitempty"`
+	Gameweek   int            `json:"gameweek"`
+	Timestamp  string         `json:"timestamp"`
+	Status     string         `json:"status"`
+	Updating   bool           `json:"updating"` // the game is being updated, the league is the last one served
+	League     []ManagerEntry `json:"league"`
+	Errors     []ManagerError `json:"errors"`
+
+	fetched time.Time // when the oldest entry was fetched from the FPL API
 }
 
+// LeagueResponse status values, degraded when some managers could not be retrieved
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+)
+
+// timestampLayout formats the timestamp of every response
+const timestampLayout = "Mon Jan _2 15:04:05 MST 2006"
+
 var fplURL = "https://fantasy.premierleague.com/api/entry/%v/"
 
 // var fplURL = "http://MIKE-DEV.local:3001/api/entry/%v/"
 // var fplURL = "http://MIKE-ALT.local:3001/api/entry/%v/"
 
+// Points writes the league as json, CORS headers for the Vercel app are added by the cors package
 func Points(w http.ResponseWriter, r *http.Request) {
-	// Set CORS headers for the preflight request
-	if r.Method == http.MethodOptions {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("Access-Control-Max-Age", "3600")
-		w.WriteHeader(http.StatusNoContent)
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
 
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
 		return
 	}
 
-	// Set CORS headers for the main request.
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-
-	managers, ok := os.LookupEnv("managers")
-	if !ok {
-		errMsg := "Environment variable -managers- can not be read"
-		log.Printf("\n*********** FATAL ERROR *********************** [%s]  **************\n", errMsg)
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprint(w, errMsg)
+	// retrieve and filter data from FPL for the list of manager ids
+	leagueResponse, err := getDataOrLast(r.Context(), managerList)
+	if err != nil {
+		writeDataError(w, err)
 
 		return
 	}
 
-	// retrieve and filter data from FPL for the list of manager ids
-	leagueResponse, err := getData(managers)
+	leagueResponse.LeagueName = leagueName
+	setCacheAge(w, leagueResponse.fetched)
+
+	// display results
+	writeJSON(w, leagueResponse)
+}
+
+// convert response to json and write it
+func writeJSON(w http.ResponseWriter, v any) {
+	response, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "%+v\n", err)
@@ -84,108 +116,203 @@ func Points(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// convert response to json
 	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%+v\n", string(response))
+}
+
+// requestManagers returns the manager ids and league name for the request, errors are written to w and ok is false
+func requestManagers(w http.ResponseWriter, r *http.Request) (managerList []string, leagueName string, ok bool) {
+	managerList, leagueName, err := getManagers(r.Context(), r)
+	if errors.Is(err, ErrUnknownLeague) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, "", false
+	}
+
+	if errors.Is(err, ErrLeagueID) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, "", false
+	}
 
-	response, err := json.MarshalIndent(leagueResponse, "", "  ")
 	if err != nil {
+		log.Printf("\n*********** FATAL ERROR *********************** [%s]  **************\n", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, "%+v\n", err)
+		fmt.Fprint(w, err)
 
-		return
+		return nil, "", false
 	}
 
-	// display results
-	fmt.Fprintf(w, "%+v\n", string(response))
+	return managerList, leagueName, true
+}
+
+// getManagers returns the manager ids and name of the configured league in path value "league",
+// or of the classic league id in query parameter "league", falling back to environment variable "managers"
+func getManagers(ctx context.Context, r *http.Request) ([]string, string, error) {
+	if slug := r.PathValue("league"); slug != "" {
+		return getLeague(ctx, slug)
+	}
+
+	if leagueID := r.URL.Query().Get("league"); leagueID != "" {
+		return getLeagueManagers(ctx, leagueID)
+	}
+
+	managers, ok := os.LookupEnv("managers")
+	if !ok {
+		return nil, "", fmt.Errorf("environment variable -managers- can not be read")
+	}
+
+	return parseManagers(managers), "", nil
+}
+
+// split a comma separated list of manager ids
+func parseManagers(managers string) []string {
+	var managerList []string
+
+	for _, manager := range strings.Split(managers, ",") {
+		if manager = strings.TrimSpace(manager); manager != "" {
+			managerList = append(managerList, manager)
+		}
+	}
+
+	return managerList
 }
 
-func getData(managers string) (LeagueResponse, error) {
+// getData returns every manager that could be retrieved, with an error for each one that could not.
+// It only fails when no manager could be retrieved or the client has gone.
+func getData(ctx context.Context, managerList []string) (LeagueResponse, error) {
 	// initialise
-	managerList := strings.Split(managers, ",")
-	league := []ManagerEntry{}                        // slice of manager gameweek entries
-	chManagerEntries := make(chan ManagerEntryResult) // channel to gather manager entries
+	league := []ManagerEntry{} // slice of manager gameweek entries
 
 	var gameweek int // var to hold the gameweek value
 
-	// loop thru manager list, fire off goroutines to get entries for each manager, results sent to channels
-	for _, manager := range managerList {
-		go getManagerEntries(strings.TrimSpace(manager), chManagerEntries)
+	var fetched time.Time // oldest entry
+
+	// get entries for each manager from a bounded pool of goroutines
+	results, err := fetchAll(ctx, managerList, getManagerEntries)
+	if err != nil {
+		return LeagueResponse{}, err
 	}
 
-	// receive results from channels, set gameweek once and build up league table
-	for range managerList {
-		managerEntries := <-chManagerEntries
-		if managerEntries.Error != nil {
-			return LeagueResponse{}, managerEntries.Error
-		}
+	// set gameweek once and build up league table and error list
+	retrieved, managerErrors, err := collectResults(results)
+	if err != nil {
+		return LeagueResponse{}, err
+	}
+
+	for _, i := range retrieved {
+		managerEntries := results[i]
 
 		gameweekResponse := managerEntries.Gameweek
 		if gameweek == 0 && gameweekResponse > 0 {
 			gameweek = gameweekResponse
 		}
 
+		if fetched.IsZero() || managerEntries.Fetched.Before(fetched) {
+			fetched = managerEntries.Fetched
+		}
+
 		league = append(league, managerEntries.ManagerEntryValue)
 	}
 
+	rankLeague(league, getTieBreaks())
+
 	// construct response
 	leagueResponse := LeagueResponse{
 		Gameweek:  gameweek,
-		Timestamp: time.Now().Format("Mon Jan _2 15:04:05 MST 2006"),
+		Timestamp: time.Now().Format(timestampLayout),
+		Status:    responseStatus(managerErrors),
 		League:    league,
+		Errors:    managerErrors,
+		fetched:   fetched,
 	}
 
 	return leagueResponse, nil
 }
 
-func getManagerEntries(entry string, chManagerEntries chan<- ManagerEntryResult) {
-	url := fmt.Sprintf(fplURL, entry)
+// newManagerError reports a manager that could not be retrieved, with the upstream status if there was a response
+func newManagerError(managerID string, err error) ManagerError {
+	managerError := ManagerError{ID: managerID, Error: err.Error()}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		chManagerEntries <- ManagerEntryResult{Error: err}
-		return
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		managerError.Status = statusErr.StatusCode
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		chManagerEntries <- ManagerEntryResult{Error: err}
-		return
-	}
+	return managerError
+}
 
-	if resp.StatusCode == http.StatusNotFound {
-		chManagerEntries <- ManagerEntryResult{
-			Gameweek:          -1,
-			ManagerEntryValue: ManagerEntry{Name: fmt.Sprintf("ID %v Not Found (404)", entry)},
+// managerResult is a result from fetchAll with the manager's ID and an error if it could not be retrieved
+type managerResult interface {
+	managerError() (string, error)
+}
+
+func (result ManagerEntryResult) managerError() (string, error) {
+	return result.ManagerID, result.Error
+}
+
+// collectResults logs every manager that could not be retrieved and returns their errors with the
+// indexes of the results that were. It fails only if no manager was retrieved, with the first error that
+// is not from the response deadline so that the cause, such as the game updating, is reported.
+func collectResults[T managerResult](results []T) ([]int, []ManagerError, error) {
+	retrieved := []int{}
+	managerErrors := []ManagerError{}
+
+	var firstErr error
+
+	for i, result := range results {
+		managerID, err := result.managerError()
+		if err != nil {
+			log.Printf("manager %v: %v", managerID, err)
+
+			if firstErr == nil || (isContextErr(firstErr) && !isContextErr(err)) {
+				firstErr = err
+			}
+
+			managerErrors = append(managerErrors, newManagerError(managerID, err))
+
+			continue
 		}
 
-		return
+		retrieved = append(retrieved, i)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		chManagerEntries <- ManagerEntryResult{Error: fmt.Errorf("get manager ID %v not OK, Status: %v", entry, resp.Status)}
-		return
+	if len(retrieved) == 0 && firstErr != nil {
+		return nil, nil, firstErr
 	}
 
-	var fplResponse Response
-	if err := json.Unmarshal(body, &fplResponse); err != nil {
-		chManagerEntries <- ManagerEntryResult{Error: err}
-		return
+	return retrieved, managerErrors, nil
+}
+
+// responseStatus is degraded if any manager could not be retrieved
+func responseStatus(managerErrors []ManagerError) string {
+	if len(managerErrors) > 0 {
+		return StatusDegraded
+	}
+
+	return StatusOK
+}
+
+func getManagerEntries(ctx context.Context, entry string) ManagerEntryResult {
+	fplResponse, fetched, err := entryResponses.get(ctx, entry)
+	if err != nil {
+		return ManagerEntryResult{ManagerID: entry, Error: fmt.Errorf("get manager ID %v %w", entry, err)}
 	}
 
 	gw := fplResponse.CurrentEvent
-	managerEntryResult := ManagerEntryResult{
-		Gameweek: gw,
+
+	return ManagerEntryResult{
+		ManagerID: entry,
+		Gameweek:  gw,
+		Fetched:   fetched,
 		ManagerEntryValue: ManagerEntry{
-			ID:       fplResponse.ID,
-			Name:     fmt.Sprintf("%v %v", fplResponse.ManagerFirstName, fplResponse.ManagerLastName),
-			Team:     fplResponse.Name,
-			Points:   fplResponse.SummaryOverallPoints,
-			Rank:     fplResponse.SummaryOverallRank,
-			GwPoints: fplResponse.SummaryEventPoints,
-			GwRank:   fplResponse.SummaryEventRank,
-			Link:     fmt.Sprintf("https://fantasy.premierleague.com/entry/%v/event/%d", entry, gw),
+			ID:              fplResponse.ID,
+			Name:            fmt.Sprintf("%v %v", fplResponse.ManagerFirstName, fplResponse.ManagerLastName),
+			Team:            fplResponse.Name,
+			Points:          fplResponse.SummaryOverallPoints,
+			Rank:            fplResponse.SummaryOverallRank,
+			GwPoints:        fplResponse.SummaryEventPoints,
+			GwRank:          fplResponse.SummaryEventRank,
+			GwTransfersCost: fplResponse.EventTransfersCost,
+			Link:            fmt.Sprintf("https://fantasy.premierleague.com/entry/%v/event/%d", entry, gw),
 		},
 	}
-	chManagerEntries <- managerEntryResult
 }
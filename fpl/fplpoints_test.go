@@ -1,7 +1,9 @@
 package fpl
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -55,15 +57,21 @@ func TestGetData(t *testing.T) {
 
 	// overwrite fplURL to use httptest URL
 	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
 
 	// ACT //////////////////////////////////////////////////////////////////////////////////////////////
-	testResponse, err := getData("1, 2")
+	testResponse, err := getData(context.Background(), []string{"1", "2"})
 	// ASSERT ///////////////////////////////////////////////////////////////////////////////////////////
 	// check err
 	if err != nil {
 		t.Errorf(`getData("1, 2") err = (%v), want: nil err`, err)
 	}
 
+	// all managers retrieved
+	if testResponse.Status != StatusOK || len(testResponse.Errors) != 0 {
+		t.Errorf(`getData Status = %v Errors = %v, want (%v) with no errors`, testResponse.Status, testResponse.Errors, StatusOK)
+	}
+
 	// correct gameweek, current event
 	if testResponse.Gameweek != Gameweek {
 		t.Errorf(`getData Gameweek = %v, want (%v)`, testResponse.Gameweek, Gameweek)
@@ -173,22 +181,77 @@ func TestFpl404(t *testing.T) {
 
 	// overwrite fplURL to use httptest URL
 	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
 
 	// ACT //////////////////////////////////////////////////////////////////////////////////////////////
-	testResponse, err := getData("1, 2")
+	testResponse, err := getData(context.Background(), []string{"1", "2"})
 	// ASSERT ///////////////////////////////////////////////////////////////////////////////////////////
 	// check err
 	if err != nil {
 		t.Errorf(`getData("1, 2") err = (%v), want: nil err`, err)
 	}
 
-	// 404 in name of unfound league entry
-	for _, leagueEntry := range testResponse.League {
-		if leagueEntry.ID != 2 {
-			if !strings.Contains(leagueEntry.Name, "404") {
-				t.Errorf(`getData manager entry = (%v), want: contains 404 in name`, leagueEntry.Name)
+	// unfound manager reported in the error list, not as a league entry
+	if testResponse.Status != StatusDegraded {
+		t.Errorf(`getData Status = %v, want (%v)`, testResponse.Status, StatusDegraded)
+	}
+
+	if len(testResponse.League) != 1 || testResponse.League[0].ID != 2 {
+		t.Errorf(`getData League = (%v), want: only manager 2`, testResponse.League)
+	}
+
+	if len(testResponse.Errors) != 1 || testResponse.Errors[0].ID != "1" || testResponse.Errors[0].Status != http.StatusNotFound {
+		t.Errorf(`getData Errors = (%v), want: manager 1 with status 404`, testResponse.Errors)
+	}
+}
+
+func TestFplPartialResults(t *testing.T) {
+	// httptest server where one manager request fails
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentType, ApplicationJSON)
+
+		switch r.URL.Path {
+		case "/1":
+			mockJSONResponse, err := json.Marshal(mockFplResponse[0])
+			if err != nil {
+				panic(err)
 			}
+
+			fmt.Fprintln(w, string(mockJSONResponse))
+		case "/2":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/3":
+			fmt.Fprintln(w, "not json")
 		}
+	}))
+	defer ts.Close()
+
+	// overwrite fplURL to use httptest URL
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+
+	// ACT //////////////////////////////////////////////////////////////////////////////////////////////
+	testResponse, err := getData(context.Background(), []string{"1", "2", "3"})
+	// ASSERT ///////////////////////////////////////////////////////////////////////////////////////////
+	if err != nil {
+		t.Fatalf(`getData("1, 2, 3") err = (%v), want: nil err`, err)
+	}
+
+	if testResponse.Status != StatusDegraded || testResponse.Gameweek != Gameweek {
+		t.Errorf(`getData Status = %v Gameweek = %v, want (%v, %v)`, testResponse.Status, testResponse.Gameweek, StatusDegraded, Gameweek)
+	}
+
+	if len(testResponse.League) != 1 || testResponse.League[0].ID != 1 {
+		t.Errorf(`getData League = (%v), want: only manager 1`, testResponse.League)
+	}
+
+	errorStatus := map[string]int{}
+	for _, managerError := range testResponse.Errors {
+		errorStatus[managerError.ID] = managerError.Status
+	}
+
+	if len(errorStatus) != 2 || errorStatus["2"] != http.StatusServiceUnavailable || errorStatus["3"] != 0 {
+		t.Errorf(`getData Errors = (%v), want: manager 2 with status 503 and manager 3`, testResponse.Errors)
 	}
 }
 
@@ -202,9 +265,10 @@ func TestFpl5XX(t *testing.T) {
 
 	// overwrite fplURL to use httptest URL
 	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
 
 	// ACT //////////////////////////////////////////////////////////////////////////////////////////////
-	_, err := getData("1, 2")
+	_, err := getData(context.Background(), []string{"1", "2"})
 
 	// ASSERT ///////////////////////////////////////////////////////////////////////////////////////////
 	if err == nil {
@@ -213,3 +277,51 @@ func TestFpl5XX(t *testing.T) {
 		t.Errorf(`getData server error (%v), want: "...not OK, Status:..."`, err)
 	}
 }
+
+func TestCollectResults(t *testing.T) {
+	errNotFound := &StatusError{StatusCode: http.StatusNotFound}
+
+	tests := []struct {
+		name      string
+		results   []ManagerEntryResult
+		retrieved []int
+		errors    int
+		wantErr   bool
+	}{
+		{"all retrieved", []ManagerEntryResult{{ManagerID: "1"}, {ManagerID: "2"}}, []int{0, 1}, 0, false},
+		{"some failed", []ManagerEntryResult{{ManagerID: "1", Error: errNotFound}, {ManagerID: "2"}}, []int{1}, 1, false},
+		{"all failed", []ManagerEntryResult{{ManagerID: "1", Error: errNotFound}}, nil, 0, true},
+		{"no managers", nil, []int{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retrieved, managerErrors, err := collectResults(tt.results)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+
+			if fmt.Sprint(retrieved) != fmt.Sprint(tt.retrieved) || len(managerErrors) != tt.errors {
+				t.Errorf("got %v and %d errors, want %v and %d", retrieved, len(managerErrors), tt.retrieved, tt.errors)
+			}
+
+			for _, managerError := range managerErrors {
+				if managerError.Status != http.StatusNotFound {
+					t.Errorf("status = %d, want %d", managerError.Status, http.StatusNotFound)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectResultsPrefersCause(t *testing.T) {
+	// the deadline passed before manager 1 was fetched, manager 2 failed as the game is updating
+	results := []ManagerEntryResult{
+		{ManagerID: "1", Error: fmt.Errorf("get manager ID 1 %w", context.DeadlineExceeded)},
+		{ManagerID: "2", Error: fmt.Errorf("get manager ID 2 %w", ErrUpdating)},
+	}
+
+	if _, _, err := collectResults(results); !errors.Is(err, ErrUpdating) {
+		t.Errorf("collectResults() err = (%v), want: %v", err, ErrUpdating)
+	}
+}
@@ -0,0 +1,179 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type HistoryResponse struct { // fields retrieved from FPL entry history API
+	Current []struct {
+		Event              int `json:"event"`
+		Points             int `json:"points"`
+		TotalPoints        int `json:"total_points"`
+		Rank               int `json:"rank"`
+		OverallRank        int `json:"overall_rank"`
+		Bank               int `json:"bank"`  // tenths of a million
+		Value              int `json:"value"` // tenths of a million
+		EventTransfers     int `json:"event_transfers"`
+		EventTransfersCost int `json:"event_transfers_cost"`
+		PointsOnBench      int `json:"points_on_bench"`
+	} `json:"current"`
+	Chips []struct {
+		Name  string `json:"name"`
+		Event int    `json:"event"`
+	} `json:"chips"`
+	Past []struct {
+		SeasonName  string `json:"season_name"`
+		TotalPoints int    `json:"total_points"`
+		Rank        int    `json:"rank"`
+	} `json:"past"`
+}
+type GameweekHistory struct { // a manager's scores and squad finances for one gameweek
+	Gameweek      int     `json:"gameweek"`
+	Points        int     `json:"points"`
+	TotalPoints   int     `json:"total_points"`
+	GwRank        int     `json:"gw_rank"`
+	OverallRank   int     `json:"overall_rank"`
+	Bank          float64 `json:"bank"`  // £m
+	Value         float64 `json:"value"` // £m, squad value including bank
+	Transfers     int     `json:"transfers"`
+	TransfersCost int     `json:"transfers_cost"` // points deducted for transfer hits
+	PointsOnBench int     `json:"points_on_bench"`
+	Chip          string  `json:"chip,omitempty"` // played in the gameweek
+}
+type SeasonHistory struct { // a manager's final total and rank for a previous season
+	Season      string `json:"season"`
+	TotalPoints int    `json:"total_points"`
+	OverallRank int    `json:"overall_rank"`
+}
+type ManagerHistory struct { // a manager's gameweeks this season and past season summaries
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Team        string            `json:"team"`
+	Gameweeks   []GameweekHistory `json:"gameweeks"`
+	PastSeasons []SeasonHistory   `json:"past_seasons"`
+}
+type ManagerHistoryResult struct { // result wrapper for ManagerHistory, Error
+	ManagerID string
+	History   ManagerHistory
+	Error     error
+}
+
+func (result ManagerHistoryResult) managerError() (string, error) {
+	return result.ManagerID, result.Error
+}
+
+type HistoryLeagueResponse struct { // response with the history of every manager
+	LeagueName string           `json:"league_name,omitempty"`
+	Timestamp  string           `json:"timestamp"`
+	Status     string           `json:"status"`
+	Managers   []ManagerHistory `json:"managers"`
+	Errors     []ManagerError   `json:"errors"`
+}
+
+var fplHistoryURL = "https://fantasy.premierleague.com/api/entry/%v/history/"
+
+// History writes the season history of every manager as json
+func History(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	historyResponse, err := getHistories(r.Context(), managerList)
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	historyResponse.LeagueName = leagueName
+
+	writeJSON(w, historyResponse)
+}
+
+// getHistories returns the history of every manager that could be retrieved, with an error for each one
+// that could not. Like getData it only fails when no manager could be retrieved or the client has gone.
+func getHistories(ctx context.Context, managerList []string) (HistoryLeagueResponse, error) {
+	managers := []ManagerHistory{}
+
+	results, err := fetchAll(ctx, managerList, getManagerHistory)
+	if err != nil {
+		return HistoryLeagueResponse{}, err
+	}
+
+	retrieved, managerErrors, err := collectResults(results)
+	if err != nil {
+		return HistoryLeagueResponse{}, err
+	}
+
+	for _, i := range retrieved {
+		managers = append(managers, results[i].History)
+	}
+
+	historyResponse := HistoryLeagueResponse{
+		Timestamp: time.Now().Format(timestampLayout),
+		Status:    responseStatus(managerErrors),
+		Managers:  managers,
+		Errors:    managerErrors,
+	}
+
+	return historyResponse, nil
+}
+
+// getManagerHistory gets the manager's name from their entry and their gameweeks from the history API
+func getManagerHistory(ctx context.Context, entry string) ManagerHistoryResult {
+	managerEntries := getManagerEntries(ctx, entry)
+	if managerEntries.Error != nil {
+		return ManagerHistoryResult{ManagerID: entry, Error: managerEntries.Error}
+	}
+
+	var fplResponse HistoryResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplHistoryURL, entry), &fplResponse); err != nil {
+		return ManagerHistoryResult{ManagerID: entry, Error: fmt.Errorf("get history for manager ID %v %w", entry, err)}
+	}
+
+	history := ManagerHistory{
+		ID:          managerEntries.ManagerEntryValue.ID,
+		Name:        managerEntries.ManagerEntryValue.Name,
+		Team:        managerEntries.ManagerEntryValue.Team,
+		Gameweeks:   make([]GameweekHistory, 0, len(fplResponse.Current)),
+		PastSeasons: make([]SeasonHistory, 0, len(fplResponse.Past)),
+	}
+
+	chips := map[int]string{} // by gameweek
+	for _, chip := range fplResponse.Chips {
+		chips[chip.Event] = chip.Name
+	}
+
+	for _, gw := range fplResponse.Current {
+		history.Gameweeks = append(history.Gameweeks, GameweekHistory{
+			Gameweek:      gw.Event,
+			Points:        gw.Points,
+			TotalPoints:   gw.TotalPoints,
+			GwRank:        gw.Rank,
+			OverallRank:   gw.OverallRank,
+			Bank:          float64(gw.Bank) / 10,
+			Value:         float64(gw.Value) / 10,
+			Transfers:     gw.EventTransfers,
+			TransfersCost: gw.EventTransfersCost,
+			PointsOnBench: gw.PointsOnBench,
+			Chip:          chips[gw.Event],
+		})
+	}
+
+	for _, season := range fplResponse.Past {
+		history.PastSeasons = append(history.PastSeasons, SeasonHistory{
+			Season:      season.SeasonName,
+			TotalPoints: season.TotalPoints,
+			OverallRank: season.Rank,
+		})
+	}
+
+	return ManagerHistoryResult{ManagerID: entry, History: history}
+}
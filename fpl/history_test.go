@@ -0,0 +1,99 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// mockHistoryServer serves manager entries and the recorded history payload for managers 1 and 2
+func mockHistoryServer(t *testing.T) {
+	t.Helper()
+
+	history, err := os.ReadFile("testdata/history.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entry/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		for _, response := range mockFplResponse {
+			if r.PathValue("id") == strconv.Itoa(response.ID) {
+				w.Header().Set(ContentType, ApplicationJSON)
+
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					panic(err)
+				}
+
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /entry/{id}/history/", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "2" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set(ContentType, ApplicationJSON)
+		w.Write(history)
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	fplURL = ts.URL + "/entry/%v/"
+	fplHistoryURL = ts.URL + "/entry/%v/history/"
+	resetCaches(t)
+}
+
+func TestGetHistories(t *testing.T) {
+	mockHistoryServer(t)
+
+	testResponse, err := getHistories(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf(`getHistories("1, 2, 3") err = (%v), want: nil err`, err)
+	}
+
+	// manager 2 has no history, manager 3 has no entry
+	if testResponse.Status != StatusDegraded || len(testResponse.Errors) != 2 {
+		t.Errorf("getHistories Status = %v Errors = %v, want (%v) with 2 errors", testResponse.Status, testResponse.Errors, StatusDegraded)
+	}
+
+	if len(testResponse.Managers) != 1 {
+		t.Fatalf("getHistories Managers = %v, want: only manager 1", testResponse.Managers)
+	}
+
+	want := ManagerHistory{
+		ID:   1,
+		Name: "first1 last1",
+		Team: "team1",
+		Gameweeks: []GameweekHistory{
+			{Gameweek: 1, Points: 64, TotalPoints: 64, GwRank: 2134567, OverallRank: 2134567, Bank: 0.5, Value: 100, PointsOnBench: 7},
+			{Gameweek: 2, Points: 51, TotalPoints: 111, GwRank: 4012345, OverallRank: 2987654, Value: 100.3, Transfers: 2, TransfersCost: 4, PointsOnBench: 3},
+		},
+		PastSeasons: []SeasonHistory{
+			{Season: "2022/23", TotalPoints: 2301, OverallRank: 412345},
+			{Season: "2023/24", TotalPoints: 2455, OverallRank: 198765},
+		},
+	}
+
+	if got := testResponse.Managers[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("getHistories manager 1\ngot :%+v\nwant:%+v", got, want)
+	}
+}
+
+func TestGetHistoriesAllFailed(t *testing.T) {
+	mockHistoryServer(t)
+
+	if _, err := getHistories(context.Background(), []string{"2", "3"}); err == nil {
+		t.Error("getHistories() err = nil, want: not OK error")
+	}
+}
@@ -0,0 +1,126 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// maxLeaguePages stops a runaway pagination loop, FPL returns 50 entries per page so leagues are cut to 10,000 managers
+const maxLeaguePages = 200
+
+type LeagueStandingsResponse struct { // fields retrieved from FPL classic league standings API
+	League struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"league"`
+	Standings struct {
+		HasNext bool          `json:"has_next"`
+		Page    int           `json:"page"`
+		Results []LeagueEntry `json:"results"`
+	} `json:"standings"`
+}
+type LeagueEntry struct { // a manager's entry in a classic league standings page
+	Entry      int    `json:"entry"`
+	EntryName  string `json:"entry_name"`
+	PlayerName string `json:"player_name"`
+	Rank       int    `json:"rank"`
+	Total      int    `json:"total"`
+}
+
+// ErrLeagueID is returned for a classic league id that is not a number
+var ErrLeagueID = errors.New("league id is not a number")
+
+var fplLeagueURL = "https://fantasy.premierleague.com/api/leagues-classic/%v/standings/?page_standings=%d"
+
+// a classic league's managers are reused for leagueManagersTTL, managers rarely join or leave
+const leagueManagersTTL = time.Hour
+
+type classicLeague struct { // the managers of a classic league and its name
+	managers []string
+	name     string
+}
+
+// a standings page and the error if it could not be retrieved
+type leaguePage struct {
+	standings LeagueStandingsResponse
+	err       error
+}
+
+// the managers of each classic league by league id
+var classicLeagues = NewCache(loadClassicLeague, func(context.Context, time.Time) time.Duration { return leagueManagersTTL })
+
+// getLeagueManagers returns the entry id of every manager in a classic league and the league's name
+func getLeagueManagers(ctx context.Context, leagueID string) ([]string, string, error) {
+	if _, err := strconv.Atoi(leagueID); err != nil {
+		return nil, "", fmt.Errorf("%q %w", leagueID, ErrLeagueID)
+	}
+
+	league, _, err := classicLeagues.get(ctx, leagueID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return league.managers, league.name, nil
+}
+
+// loadClassicLeague pages through a classic league's standings, maxWorkers pages at a time, until the last page.
+// Entries can move between pages while paging, so ids are only returned once. A league with more than
+// maxLeaguePages pages is cut to the managers on those pages.
+func loadClassicLeague(ctx context.Context, leagueID string) (classicLeague, error) {
+	league := classicLeague{}
+	seen := map[int]bool{}
+
+	for first := 1; first <= maxLeaguePages; first += maxWorkers {
+		pages := []string{}
+		for page := first; page < first+maxWorkers && page <= maxLeaguePages; page++ {
+			pages = append(pages, strconv.Itoa(page))
+		}
+
+		results, err := fetchAll(ctx, pages, func(ctx context.Context, page string) leaguePage {
+			n, _ := strconv.Atoi(page)
+			standings, err := getLeaguePage(ctx, leagueID, n)
+
+			return leaguePage{standings: standings, err: err}
+		})
+		if err != nil {
+			return classicLeague{}, err
+		}
+
+		// pages after the last one are not needed whether or not they were retrieved
+		for _, result := range results {
+			if result.err != nil {
+				return classicLeague{}, result.err
+			}
+
+			league.name = result.standings.League.Name
+
+			for _, entry := range result.standings.Standings.Results {
+				if !seen[entry.Entry] {
+					seen[entry.Entry] = true
+					league.managers = append(league.managers, strconv.Itoa(entry.Entry))
+				}
+			}
+
+			if !result.standings.Standings.HasNext {
+				return league, nil
+			}
+		}
+	}
+
+	log.Printf("league %v has more than %d pages of standings, only their managers are fetched", leagueID, maxLeaguePages)
+
+	return league, nil
+}
+
+func getLeaguePage(ctx context.Context, leagueID string, page int) (LeagueStandingsResponse, error) {
+	var standings LeagueStandingsResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplLeagueURL, leagueID, page), &standings); err != nil {
+		return LeagueStandingsResponse{}, fmt.Errorf("get league ID %v page %d %w", leagueID, page, err)
+	}
+
+	return standings, nil
+}
@@ -0,0 +1,214 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockLeaguePages returns a classic league standings server with pages of entry ids, pages after the last are empty
+func mockLeaguePages(t *testing.T, pages [][]int) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/leagues-classic/314/standings/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page, err := strconv.Atoi(r.URL.Query().Get("page_standings"))
+		if err != nil || page < 1 || page > maxLeaguePages {
+			t.Errorf("page_standings = %q, want 1 to %d", r.URL.Query().Get("page_standings"), maxLeaguePages)
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var response LeagueStandingsResponse
+		response.League.ID = 314
+		response.League.Name = "Pub League"
+		response.Standings.Page = page
+		response.Standings.HasNext = page < len(pages)
+
+		if page <= len(pages) {
+			for _, entry := range pages[page-1] {
+				response.Standings.Results = append(response.Standings.Results, LeagueEntry{Entry: entry})
+			}
+		}
+
+		w.Header().Set(ContentType, ApplicationJSON)
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			panic(err)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	fplLeagueURL = ts.URL + "/leagues-classic/%v/standings/?page_standings=%d"
+	resetCaches(t)
+
+	return ts
+}
+
+func TestGetLeagueManagers(t *testing.T) {
+	// entry 5 drops from page 1 to page 2 between requests so appears twice
+	mockLeaguePages(t, [][]int{{1, 2, 3, 4, 5}, {5, 6, 7}, {8}})
+
+	managerList, leagueName, err := getLeagueManagers(context.Background(), "314")
+	if err != nil {
+		t.Fatalf(`getLeagueManagers(context.Background(), "314") err = (%v), want: nil err`, err)
+	}
+
+	want := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	if !reflect.DeepEqual(managerList, want) || leagueName != "Pub League" {
+		t.Errorf(`getLeagueManagers(context.Background(), "314") = %v, %q, want (%v), "Pub League"`, managerList, leagueName, want)
+	}
+}
+
+func TestGetLeagueManagersErrors(t *testing.T) {
+	mockLeaguePages(t, [][]int{{1}})
+
+	if _, _, err := getLeagueManagers(context.Background(), "abc"); !errors.Is(err, ErrLeagueID) {
+		t.Errorf(`getLeagueManagers(context.Background(), "abc") err = (%v), want: ErrLeagueID`, err)
+	}
+
+	if _, _, err := getLeagueManagers(context.Background(), "999"); err == nil || !strings.Contains(err.Error(), "not OK, Status:") {
+		t.Errorf(`getLeagueManagers(context.Background(), "999") err = (%v), want: "...not OK, Status:..."`, err)
+	}
+}
+
+func TestGetLeagueManagersLargeLeague(t *testing.T) {
+	// 120 pages of 50 entries
+	pages := make([][]int, 120)
+	for page := range pages {
+		for i := range 50 {
+			pages[page] = append(pages[page], page*50+i+1)
+		}
+	}
+
+	mockLeaguePages(t, pages)
+
+	managerList, _, err := getLeagueManagers(context.Background(), "314")
+	if err != nil {
+		t.Fatalf(`getLeagueManagers(context.Background(), "314") err = (%v), want: nil err`, err)
+	}
+
+	if len(managerList) != 6000 || managerList[5999] != fmt.Sprint(6000) {
+		t.Errorf(`getLeagueManagers(context.Background(), "314") = %d managers ending %v, want 6000`, len(managerList), managerList[len(managerList)-1])
+	}
+}
+
+func TestGetLeagueManagersCut(t *testing.T) {
+	// 210 pages of 50 entries
+	pages := make([][]int, 210)
+	for page := range pages {
+		for i := range 50 {
+			pages[page] = append(pages[page], page*50+i+1)
+		}
+	}
+
+	mockLeaguePages(t, pages)
+
+	managerList, _, err := getLeagueManagers(context.Background(), "314")
+	if err != nil || len(managerList) != maxLeaguePages*50 {
+		t.Errorf(`getLeagueManagers(context.Background(), "314") = %d managers, (%v), want %d`, len(managerList), err, maxLeaguePages*50)
+	}
+}
+
+func TestGetLeagueManagersDeadline(t *testing.T) {
+	// no page is sent until the first maxWorkers pages have been requested, paging one at a time hits the deadline
+	requested := make(chan struct{})
+
+	var requests atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == maxWorkers {
+			close(requested)
+		}
+
+		select {
+		case <-requested:
+		case <-r.Context().Done():
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page_standings"))
+
+		var response LeagueStandingsResponse
+		response.League.Name = "Pub League"
+		response.Standings.HasNext = page < 3
+
+		if page <= 3 {
+			response.Standings.Results = []LeagueEntry{{Entry: page}}
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			panic(err)
+		}
+	}))
+	defer ts.Close()
+
+	fplLeagueURL = ts.URL + "/leagues-classic/%v/standings/?page_standings=%d"
+	resetCaches(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	managerList, _, err := getLeagueManagers(ctx, "314")
+	if err != nil || !reflect.DeepEqual(managerList, []string{"1", "2", "3"}) {
+		t.Fatalf(`getLeagueManagers(ctx, "314") = %v, (%v), want: [1 2 3]`, managerList, err)
+	}
+
+	// the league is reused, the standings are not requested again
+	ts.Close()
+
+	managerList, _, err = getLeagueManagers(ctx, "314")
+	if err != nil || len(managerList) != 3 || requests.Load() != maxWorkers {
+		t.Errorf(`getLeagueManagers(ctx, "314") again = %v, (%v) after %d requests, want 3 managers after %d`, managerList, err, requests.Load(), maxWorkers)
+	}
+}
+
+func TestParseManagers(t *testing.T) {
+	want := []string{"1249240", "315912", "1505746"}
+	if got := parseManagers(" 1249240, 315912,,1505746 , "); !reflect.DeepEqual(got, want) {
+		t.Errorf(`parseManagers() = %v, want (%v)`, got, want)
+	}
+}
+
+func TestPointsClassicLeague(t *testing.T) {
+	mockLeaguePages(t, [][]int{{1, 2}})
+
+	ts := setTestServer()
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+
+	rec := httptest.NewRecorder()
+	Points(rec, httptest.NewRequest(http.MethodGet, "/fpl?league=314", http.NoBody))
+
+	var leagueResponse LeagueResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &leagueResponse); err != nil {
+		t.Fatalf("Points() body %q: %v", rec.Body.String(), err)
+	}
+
+	if leagueResponse.LeagueName != "Pub League" || len(leagueResponse.League) != 2 {
+		t.Errorf("Points() league_name = %q with %d managers, want Pub League with 2", leagueResponse.LeagueName, len(leagueResponse.League))
+	}
+
+	rec = httptest.NewRecorder()
+	Points(rec, httptest.NewRequest(http.MethodGet, "/fpl?league=abc", http.NoBody))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Points() league=abc = %d %q, want 400", rec.Code, rec.Body.String())
+	}
+}
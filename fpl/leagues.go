@@ -0,0 +1,159 @@
+package fpl
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// leagues file read when environment variable "leagues_file" is not set
+const defaultLeaguesFile = "leagues.json"
+
+// paths of the fixed /fpl routes, a league with one of them as its slug could never be reached
+var reservedSlugs = map[string]bool{
+	"table": true, "history": true, "live": true, "awards": true, "ownership": true,
+	"leagues": true, "stream": true, "chart": true, "transfers": true,
+}
+
+// ErrUnknownLeague is returned for a league name that is not in the leagues file
+var ErrUnknownLeague = errors.New("league is not configured")
+
+type LeaguesConfig struct { // named leagues read from the leagues file
+	Leagues []League `json:"leagues"`
+}
+type League struct { // a named league of managers listed by entry id or from a classic league
+	Slug     string      `json:"slug"`
+	Name     string      `json:"name"`
+	Managers []int       `json:"managers,omitempty"`
+	LeagueID int         `json:"league_id,omitempty"`
+	Prizes   *PrizeRules `json:"prizes,omitempty"`
+}
+type LeagueLink struct { // a configured league in the index
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	Link string `json:"link"`
+}
+
+// Leagues writes the index of configured leagues as json
+func Leagues(w http.ResponseWriter, _ *http.Request) {
+	leagues, err := loadLeagues()
+	if err != nil {
+		log.Printf("\n*********** FATAL ERROR *********************** [%s]  **************\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, err)
+
+		return
+	}
+
+	index := make([]LeagueLink, 0, len(leagues))
+	for _, league := range leagues {
+		index = append(index, LeagueLink{Slug: league.Slug, Name: league.Name, Link: "/fpl/" + league.Slug})
+	}
+
+	writeJSON(w, index)
+}
+
+// loadLeagues reads the leagues file named in environment variable "leagues_file", or defaultLeaguesFile.
+// A missing default file configures no leagues.
+func loadLeagues() ([]League, error) {
+	path, ok := os.LookupEnv("leagues_file")
+	if !ok {
+		path = defaultLeaguesFile
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !ok {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read leagues file: %w", err)
+	}
+
+	var config LeaguesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse leagues file %v: %w", path, err)
+	}
+
+	if err := validateLeagues(config.Leagues); err != nil {
+		return nil, fmt.Errorf("leagues file %v: %w", path, err)
+	}
+
+	return config.Leagues, nil
+}
+
+// every league needs a unique slug, that is not a fixed route, and either managers or a classic league id
+func validateLeagues(leagues []League) error {
+	seen := map[string]bool{}
+
+	for i, league := range leagues {
+		switch {
+		case league.Slug == "":
+			return fmt.Errorf("league %d has no slug", i+1)
+		case reservedSlugs[league.Slug]:
+			return fmt.Errorf("league %q slug is reserved for /fpl/%s", league.Slug, league.Slug)
+		case seen[league.Slug]:
+			return fmt.Errorf("league %q is configured twice", league.Slug)
+		case (len(league.Managers) == 0) == (league.LeagueID == 0):
+			return fmt.Errorf("league %q needs either managers or a league_id", league.Slug)
+		case league.Prizes != nil:
+			if err := league.Prizes.validate(); err != nil {
+				return fmt.Errorf("league %q prizes: %w", league.Slug, err)
+			}
+		}
+
+		seen[league.Slug] = true
+	}
+
+	return nil
+}
+
+// findLeague returns the configured league with the slug
+func findLeague(slug string) (League, error) {
+	leagues, err := loadLeagues()
+	if err != nil {
+		return League{}, err
+	}
+
+	for _, league := range leagues {
+		if league.Slug == slug {
+			return league, nil
+		}
+	}
+
+	return League{}, fmt.Errorf("%q %w", slug, ErrUnknownLeague)
+}
+
+// getLeague returns the manager ids and display name of the configured league with the slug,
+// a classic league without a name has its name from FPL
+func getLeague(ctx context.Context, slug string) ([]string, string, error) {
+	league, err := findLeague(slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if league.LeagueID != 0 {
+		managerList, classicName, err := getLeagueManagers(ctx, strconv.Itoa(league.LeagueID))
+
+		return managerList, cmp.Or(league.Name, classicName, league.Slug), err
+	}
+
+	name := league.Name
+	if name == "" {
+		name = league.Slug
+	}
+
+	managerList := make([]string, len(league.Managers))
+	for i, manager := range league.Managers {
+		managerList[i] = strconv.Itoa(manager)
+	}
+
+	return managerList, name, nil
+}
@@ -0,0 +1,153 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeLeaguesFile writes a leagues file and points environment variable "leagues_file" at it
+func writeLeaguesFile(t *testing.T, config string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "leagues.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("leagues_file", path)
+}
+
+const testLeagues = `{"leagues": [
+	{"slug": "work", "name": "Work League", "managers": [1, 2]},
+	{"slug": "pub", "league_id": 314}
+]}`
+
+func TestGetLeague(t *testing.T) {
+	writeLeaguesFile(t, testLeagues)
+	mockLeaguePages(t, [][]int{{7, 8}})
+
+	tests := []struct {
+		slug     string
+		managers []string
+		name     string
+	}{
+		{"work", []string{"1", "2"}, "Work League"},
+		{"pub", []string{"7", "8"}, "Pub League"}, // no name shows the classic league's name
+	}
+
+	for _, test := range tests {
+		managerList, name, err := getLeague(context.Background(), test.slug)
+		if err != nil {
+			t.Fatalf("getLeague(%q) err = (%v), want: nil err", test.slug, err)
+		}
+
+		if !reflect.DeepEqual(managerList, test.managers) || name != test.name {
+			t.Errorf("getLeague(%q) = %v, %q, want %v, %q", test.slug, managerList, name, test.managers, test.name)
+		}
+	}
+
+	if _, _, err := getLeague(context.Background(), "family"); !errors.Is(err, ErrUnknownLeague) {
+		t.Errorf(`getLeague("family") err = (%v), want: ErrUnknownLeague`, err)
+	}
+}
+
+func TestLoadLeaguesInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no slug":         `{"leagues": [{"managers": [1]}]}`,
+		"duplicate slug":  `{"leagues": [{"slug": "a", "managers": [1]}, {"slug": "a", "league_id": 2}]}`,
+		"reserved slug":   `{"leagues": [{"slug": "live", "managers": [1]}]}`,
+		"no managers":     `{"leagues": [{"slug": "a"}]}`,
+		"managers and id": `{"leagues": [{"slug": "a", "managers": [1], "league_id": 2}]}`,
+		"not json":        `leagues`,
+	}
+
+	for scenario, config := range tests {
+		writeLeaguesFile(t, config)
+
+		if _, err := loadLeagues(); err == nil {
+			t.Errorf("loadLeagues() %s err = nil, want: error", scenario)
+		}
+	}
+}
+
+func TestLoadLeaguesMissingFile(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// no default file configures no leagues
+	if leagues, err := loadLeagues(); err != nil || len(leagues) != 0 {
+		t.Errorf("loadLeagues() = %v, (%v), want: no leagues, nil err", leagues, err)
+	}
+
+	// a named file must exist
+	t.Setenv("leagues_file", "missing.json")
+
+	if _, err := loadLeagues(); err == nil {
+		t.Error("loadLeagues() missing leagues_file err = nil, want: error")
+	}
+}
+
+func TestLeaguesIndex(t *testing.T) {
+	writeLeaguesFile(t, testLeagues)
+
+	rec := httptest.NewRecorder()
+	Leagues(rec, httptest.NewRequest(http.MethodGet, "/fpl/leagues", http.NoBody))
+
+	var index []LeagueLink
+	if err := json.Unmarshal(rec.Body.Bytes(), &index); err != nil {
+		t.Fatalf("Leagues() body %q: %v", rec.Body.String(), err)
+	}
+
+	want := []LeagueLink{{"work", "Work League", "/fpl/work"}, {"pub", "", "/fpl/pub"}}
+	if !reflect.DeepEqual(index, want) {
+		t.Errorf("Leagues() = %v, want %v", index, want)
+	}
+}
+
+func TestPointsNamedLeague(t *testing.T) {
+	writeLeaguesFile(t, testLeagues)
+
+	ts := setTestServer()
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /fpl/{league}", Points)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fpl/work", http.NoBody))
+
+	var leagueResponse LeagueResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &leagueResponse); err != nil {
+		t.Fatalf("Points() body %q: %v", rec.Body.String(), err)
+	}
+
+	if leagueResponse.LeagueName != "Work League" || len(leagueResponse.League) != 2 {
+		t.Errorf("Points() league_name = %q with %d managers, want Work League with 2", leagueResponse.LeagueName, len(leagueResponse.League))
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fpl/family", http.NoBody))
+
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "not configured") {
+		t.Errorf("Points() unknown league = %d %q, want 404", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,406 @@
+package fpl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// chips that change how picks are scored
+const (
+	ChipTripleCaptain = "3xc"
+	ChipBenchBoost    = "bboost"
+)
+
+// picks in positions 1 to 11 start, the rest are on the bench in substitution order
+const startingPlayers = 11
+
+// element types of picks
+const (
+	Goalkeeper = 1
+	Defender   = 2
+	Midfielder = 3
+	Forward    = 4
+)
+
+// fewest players of an element type a starting XI can have after automatic substitutions
+var minFormation = map[int]int{Goalkeeper: 1, Defender: 3, Forward: 1}
+
+type PicksResponse struct { // fields retrieved from FPL entry event picks API
+	ActiveChip   string `json:"active_chip"`
+	EntryHistory struct {
+		Event              int `json:"event"`
+		Points             int `json:"points"`       // before transfer hits
+		TotalPoints        int `json:"total_points"` // after transfer hits
+		OverallRank        int `json:"overall_rank"`
+		EventTransfersCost int `json:"event_transfers_cost"`
+		PointsOnBench      int `json:"points_on_bench"`
+	} `json:"entry_history"`
+	Picks []Pick `json:"picks"`
+}
+type Pick struct { // a player in a manager's squad for a gameweek
+	Element       int  `json:"element"`
+	Position      int  `json:"position"`
+	IsCaptain     bool `json:"is_captain"`
+	IsViceCaptain bool `json:"is_vice_captain"`
+	ElementType   int  `json:"element_type"`
+}
+type LiveElementsResponse struct { // fields retrieved from FPL event live API
+	Elements []struct {
+		ID    int          `json:"id"`
+		Stats ElementStats `json:"stats"`
+	} `json:"elements"`
+}
+type ElementStats struct { // a player's live stats for the gameweek with the status of their team's fixtures
+	Minutes     int  `json:"minutes"`
+	TotalPoints int  `json:"total_points"`
+	Started     bool `json:"-"` // a fixture of the player's team has kicked off
+	Finished    bool `json:"-"` // every fixture of the player's team is over, or they have none
+}
+type Fixture struct { // fields retrieved from FPL fixtures API
+	ID                  int  `json:"id"`
+	TeamH               int  `json:"team_h"`
+	TeamA               int  `json:"team_a"`
+	Started             bool `json:"started"`
+	FinishedProvisional bool `json:"finished_provisional"` // at the final whistle, before bonus points are confirmed
+}
+type LivePick struct { // a pick with its live points
+	Element             int  `json:"element"`
+	Position            int  `json:"position"`
+	Minutes             int  `json:"minutes"`
+	Points              int  `json:"points"`
+	Multiplier          int  `json:"multiplier"`
+	ProjectedMultiplier int  `json:"projected_multiplier"` // after automatic substitutions
+	Captain             bool `json:"captain"`
+	ViceCaptain         bool `json:"vice_captain"`
+}
+type AutoSub struct { // a bench player replacing a starter whose fixtures are finished without minutes
+	ElementOut int `json:"element_out"`
+	ElementIn  int `json:"element_in"`
+}
+type LiveScore struct { // a manager's live gameweek score, current and projected after automatic substitutions
+	Chip            string     `json:"chip"`
+	TransfersCost   int        `json:"transfers_cost"`
+	CurrentPoints   int        `json:"current_points"`
+	ProjectedPoints int        `json:"projected_points"`
+	AutoSubs        []AutoSub  `json:"auto_subs"`
+	Picks           []LivePick `json:"picks"`
+}
+type LiveEntry struct { // a manager entry with points and gw_points, before hits, from live scores
+	ManagerEntry
+	LiveScore
+}
+type LiveLeagueResponse struct { // response with array of live manager entries
+	LeagueName string         `json:"league_name,omitempty"`
+	Gameweek   int            `json:"gameweek"`
+	Timestamp  string         `json:"timestamp"`
+	Status     string         `json:"status"`
+	League     []LiveEntry    `json:"league"`
+	Errors     []ManagerError `json:"errors"`
+}
+type LiveScoreResult struct { // result wrapper for LiveScore, Error
+	ManagerID string
+	Score     LiveScore
+	Error     error
+}
+
+func (result LiveScoreResult) managerError() (string, error) { return result.ManagerID, result.Error }
+
+var (
+	fplPicksURL    = "https://fantasy.premierleague.com/api/entry/%v/event/%d/picks/"
+	fplLiveURL     = "https://fantasy.premierleague.com/api/event/%d/live/"
+	fplFixturesURL = "https://fantasy.premierleague.com/api/fixtures/?event=%d"
+)
+
+// Live writes the league scored from each manager's picks and live player stats as json
+func Live(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	liveResponse, err := getLive(r.Context(), managerList)
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	liveResponse.LeagueName = leagueName
+
+	writeJSON(w, liveResponse)
+}
+
+// getLive gets the league from getData, then replaces each manager's gameweek points, and their total,
+// with a score from their picks and the gameweek's live stats. Like the summary points, gw_points are
+// before transfer hits and points after them. The league is ranked on the live totals.
+func getLive(ctx context.Context, managerList []string) (LiveLeagueResponse, error) {
+	leagueResponse, err := getData(ctx, managerList)
+	if err != nil {
+		return LiveLeagueResponse{}, err
+	}
+
+	live, err := getLiveStats(ctx, leagueResponse.Gameweek)
+	if err != nil {
+		return LiveLeagueResponse{}, err
+	}
+
+	ids := make([]string, len(leagueResponse.League))
+	for i, entry := range leagueResponse.League {
+		ids[i] = strconv.Itoa(entry.ID)
+	}
+
+	results, err := fetchAll(ctx, ids, func(ctx context.Context, manager string) LiveScoreResult {
+		return getLiveScore(ctx, manager, leagueResponse.Gameweek, live)
+	})
+	if err != nil {
+		return LiveLeagueResponse{}, err
+	}
+
+	league := []ManagerEntry{}
+	scores := map[int]LiveScore{}
+
+	retrieved, managerErrors, err := collectResults(results)
+	if err != nil {
+		return LiveLeagueResponse{}, err
+	}
+
+	managerErrors = slices.Concat(leagueResponse.Errors, managerErrors)
+
+	for _, i := range retrieved {
+		result := results[i]
+
+		entry := leagueResponse.League[i]
+		// summary gameweek points are before the transfer hit already taken from the total
+		gwPoints := result.Score.CurrentPoints + result.Score.TransfersCost
+		entry.Points += gwPoints - entry.GwPoints
+		entry.GwPoints = gwPoints
+		entry.GwTransfersCost = result.Score.TransfersCost
+
+		league = append(league, entry)
+		scores[entry.ID] = result.Score
+	}
+
+	rankLeague(league, getTieBreaks())
+
+	liveResponse := LiveLeagueResponse{
+		Gameweek:  leagueResponse.Gameweek,
+		Timestamp: time.Now().Format(timestampLayout),
+		Status:    responseStatus(managerErrors),
+		League:    make([]LiveEntry, 0, len(league)),
+		Errors:    managerErrors,
+	}
+
+	for _, entry := range league {
+		liveResponse.League = append(liveResponse.League, LiveEntry{ManagerEntry: entry, LiveScore: scores[entry.ID]})
+	}
+
+	return liveResponse, nil
+}
+
+// getLiveStats returns the live stats of every player for the gameweek by element id, with whether their
+// team's fixtures have started or finished from the gameweek's fixtures and each player's team in bootstrap-static
+func getLiveStats(ctx context.Context, gameweek int) (map[int]ElementStats, error) {
+	var fplResponse LiveElementsResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplLiveURL, gameweek), &fplResponse); err != nil {
+		return nil, fmt.Errorf("get live stats for gameweek %d %w", gameweek, err)
+	}
+
+	var fixtures []Fixture
+	if err := getJSON(ctx, fmt.Sprintf(fplFixturesURL, gameweek), &fixtures); err != nil {
+		return nil, fmt.Errorf("get fixtures for gameweek %d %w", gameweek, err)
+	}
+
+	bootstrap, err := reference.Bootstrap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	started, unfinished := map[int]bool{}, map[int]bool{} // teams with a fixture that has kicked off, or is not over
+	for _, fixture := range fixtures {
+		for _, team := range []int{fixture.TeamH, fixture.TeamA} {
+			started[team] = started[team] || fixture.Started
+			unfinished[team] = unfinished[team] || !fixture.FinishedProvisional
+		}
+	}
+
+	live := make(map[int]ElementStats, len(fplResponse.Elements))
+	for _, element := range fplResponse.Elements {
+		live[element.ID] = element.Stats
+	}
+
+	for _, element := range bootstrap.Elements {
+		stats := live[element.ID]
+		stats.Started, stats.Finished = started[element.Team], !unfinished[element.Team]
+		live[element.ID] = stats
+	}
+
+	return live, nil
+}
+
+func getLiveScore(ctx context.Context, entry string, gameweek int, live map[int]ElementStats) LiveScoreResult {
+	picks, err := getPicks(ctx, entry, gameweek)
+	if err != nil {
+		return LiveScoreResult{ManagerID: entry, Error: err}
+	}
+
+	return LiveScoreResult{ManagerID: entry, Score: scorePicks(picks, live)}
+}
+
+func getPicks(ctx context.Context, entry string, gameweek int) (PicksResponse, error) {
+	var fplResponse PicksResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplPicksURL, entry, gameweek), &fplResponse); err != nil {
+		return PicksResponse{}, fmt.Errorf("get picks for manager ID %v %w", entry, err)
+	}
+
+	return fplResponse, nil
+}
+
+// scorePicks applies the gameweek's chip, the captaincy and transfer hits to the live points of the picks,
+// for the current team and after the automatic substitutions.
+// The vice-captain takes the armband when the captain's fixtures are finished without minutes and they have some.
+func scorePicks(picks PicksResponse, live map[int]ElementStats) LiveScore {
+	score := LiveScore{
+		Chip:            picks.ActiveChip,
+		TransfersCost:   picks.EntryHistory.EventTransfersCost,
+		CurrentPoints:   -picks.EntryHistory.EventTransfersCost,
+		ProjectedPoints: -picks.EntryHistory.EventTransfersCost,
+		AutoSubs:        autoSubs(picks, live),
+		Picks:           make([]LivePick, 0, len(picks.Picks)),
+	}
+
+	captainMultiplier := 2
+	if picks.ActiveChip == ChipTripleCaptain {
+		captainMultiplier = 3
+	}
+
+	var captain, viceCaptain int // element ids
+
+	for _, pick := range picks.Picks {
+		if pick.IsCaptain {
+			captain = pick.Element
+		}
+
+		if pick.IsViceCaptain {
+			viceCaptain = pick.Element
+		}
+	}
+
+	armband := captain
+	if live[captain].Finished && live[captain].Minutes == 0 && live[viceCaptain].Minutes > 0 {
+		armband = viceCaptain
+	}
+
+	subbed := map[int]int{} // projected multiplier of players swapped by automatic substitutions
+	for _, sub := range score.AutoSubs {
+		subbed[sub.ElementOut] = 0
+		subbed[sub.ElementIn] = 1
+	}
+
+	for _, pick := range picks.Picks {
+		multiplier := 1
+
+		switch {
+		case pick.Position > startingPlayers && picks.ActiveChip != ChipBenchBoost:
+			multiplier = 0
+		case pick.Element == armband:
+			multiplier = captainMultiplier
+		}
+
+		projectedMultiplier, ok := subbed[pick.Element]
+		if !ok {
+			projectedMultiplier = multiplier
+		}
+
+		points := live[pick.Element].TotalPoints
+		score.CurrentPoints += points * multiplier
+		score.ProjectedPoints += points * projectedMultiplier
+
+		score.Picks = append(score.Picks, LivePick{
+			Element:             pick.Element,
+			Position:            pick.Position,
+			Minutes:             live[pick.Element].Minutes,
+			Points:              points,
+			Multiplier:          multiplier,
+			ProjectedMultiplier: projectedMultiplier,
+			Captain:             pick.IsCaptain,
+			ViceCaptain:         pick.IsViceCaptain,
+		})
+	}
+
+	return score
+}
+
+// autoSubs lists the automatic substitutions as they stand. Each starter whose fixtures are finished without
+// them playing, in team order, is replaced by the first bench player who has played and leaves a valid formation.
+// A bench player whose fixtures are not finished may yet play, so the substitution waits for them. Goalkeepers
+// are only replaced by goalkeepers and there are no substitutions with bench boost.
+func autoSubs(picks PicksResponse, live map[int]ElementStats) []AutoSub {
+	subs := []AutoSub{}
+
+	if picks.ActiveChip == ChipBenchBoost {
+		return subs
+	}
+
+	squad := slices.Clone(picks.Picks)
+	slices.SortFunc(squad, func(a, b Pick) int { return cmp.Compare(a.Position, b.Position) })
+
+	formation := map[int]int{} // starters of each element type
+	for _, pick := range squad {
+		if pick.Position <= startingPlayers {
+			formation[pick.ElementType]++
+		}
+	}
+
+	used := map[int]bool{} // bench players already brought on
+
+	for _, starter := range squad {
+		if starter.Position > startingPlayers || !live[starter.Element].Finished || live[starter.Element].Minutes > 0 {
+			continue
+		}
+
+		for _, sub := range squad {
+			if sub.Position <= startingPlayers || used[sub.Element] ||
+				(sub.ElementType == Goalkeeper) != (starter.ElementType == Goalkeeper) {
+				continue
+			}
+
+			formation[starter.ElementType]--
+			formation[sub.ElementType]++
+
+			valid := validFormation(formation)
+			if stats := live[sub.Element]; valid && stats.Started && stats.Minutes > 0 {
+				used[sub.Element] = true
+				subs = append(subs, AutoSub{ElementOut: starter.Element, ElementIn: sub.Element})
+
+				break
+			}
+
+			formation[sub.ElementType]--
+			formation[starter.ElementType]++
+
+			if valid && !live[sub.Element].Finished {
+				break // they may yet play
+			}
+		}
+	}
+
+	return subs
+}
+
+func validFormation(formation map[int]int) bool {
+	for elementType, minimum := range minFormation {
+		if formation[elementType] < minimum {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,240 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readTestdata unmarshals a recorded FPL payload from testdata
+func readTestdata(t *testing.T, name string, v any) {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("unmarshal %v: %v", name, err)
+	}
+}
+
+// liveStats returns the recorded live stats once every fixture is finished, with the blank players given
+// no minutes or points and the unplayed players' fixtures not yet started
+func liveStats(t *testing.T, blank, unplayed []int) map[int]ElementStats {
+	t.Helper()
+
+	var fplResponse LiveElementsResponse
+	readTestdata(t, "live.json", &fplResponse)
+
+	live := map[int]ElementStats{}
+	for _, element := range fplResponse.Elements {
+		stats := element.Stats
+		stats.Started, stats.Finished = true, true
+		live[element.ID] = stats
+	}
+
+	for _, element := range blank {
+		live[element] = ElementStats{Started: true, Finished: true}
+	}
+
+	for _, element := range unplayed {
+		live[element] = ElementStats{}
+	}
+
+	return live
+}
+
+// mockFixtures serves testdata/fixtures.json, with every fixture of the gameweek finished, and
+// testdata/bootstrap.json for the players' teams
+func mockFixtures(t *testing.T) {
+	t.Helper()
+
+	fixtures, err := os.ReadFile("testdata/fixtures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(fixtures)
+	}))
+	t.Cleanup(ts.Close)
+
+	saved := fplFixturesURL
+	fplFixturesURL = ts.URL + "/fixtures/?event=%d"
+	t.Cleanup(func() { fplFixturesURL = saved })
+
+	fixtureReference(t)
+}
+
+func TestGetLiveStatsFixtures(t *testing.T) {
+	fixtureReference(t)
+
+	fixtures := []Fixture{{TeamH: 1, TeamA: 2, Started: true, FinishedProvisional: true}, {TeamH: 8, TeamA: 2, Started: true}, {TeamH: 4, TeamA: 5}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "fixtures") {
+			json.NewEncoder(w).Encode(fixtures)
+			return
+		}
+
+		w.Write([]byte(`{"elements": []}`))
+	}))
+	defer ts.Close()
+
+	fplLiveURL = ts.URL + "/event/%d/live/"
+	fplFixturesURL = ts.URL + "/fixtures/?event=%d"
+
+	live, err := getLiveStats(context.Background(), 98)
+	if err != nil {
+		t.Fatalf("getLiveStats() err = %v, want: nil err", err)
+	}
+
+	// element 1 of team 1 finished, 14 of team 2 still playing a second fixture, 4 of team 8 playing,
+	// 5 of team 6 has no fixture and 6 of team 4 has not kicked off
+	tests := []struct {
+		element           int
+		started, finished bool
+	}{{1, true, true}, {14, true, false}, {4, true, false}, {5, false, true}, {6, false, false}}
+
+	for _, test := range tests {
+		if stats := live[test.element]; stats.Started != test.started || stats.Finished != test.finished {
+			t.Errorf("getLiveStats() element %d started %v finished %v, want %v %v",
+				test.element, stats.Started, stats.Finished, test.started, test.finished)
+		}
+	}
+}
+
+func TestScorePicks(t *testing.T) {
+	// starting XI score 44, captain 10 scores 13, vice-captain 6 scores 8, bench scores 7
+	// defender 5 has no minutes so bench defender 13 scoring 2 comes on
+	tests := []struct {
+		scenario  string
+		picks     string
+		blank     []int
+		unplayed  []int
+		current   int
+		projected int
+		armband   int
+		subs      []AutoSub
+	}{
+		{"captain doubled less a 4 point hit", "picks.json", nil, nil, 44 + 13 - 4, 55, 10, []AutoSub{{5, 13}}},
+		{"triple captain", "picks_3xc.json", nil, nil, 44 + 2*13, 72, 10, []AutoSub{{5, 13}}},
+		{"bench boost scores the bench without substitutions", "picks_bboost.json", nil, nil, 44 + 13 + 7, 64, 10, []AutoSub{}},
+		{"vice-captain takes the armband", "picks.json", []int{10}, nil, 44 - 13 + 8 - 4, 38, 6, []AutoSub{{5, 13}, {10, 14}}},
+		{"vice-captain triple captain", "picks_3xc.json", []int{10}, nil, 44 - 13 + 2*8, 50, 6, []AutoSub{{5, 13}, {10, 14}}},
+		{"captain yet to play keeps the armband", "picks.json", nil, []int{10}, 44 - 13 - 4, 29, 10, []AutoSub{{5, 13}}},
+		{"captain keeps the armband when both blank", "picks.json", []int{10, 6}, nil, 44 - 13 - 8 - 4, 23, 10, []AutoSub{{5, 13}, {6, 14}, {10, 15}}},
+		{"goalkeeper replaced by bench goalkeeper", "picks.json", []int{1}, nil, 44 - 6 + 13 - 4, 52, 10, []AutoSub{{1, 12}, {5, 13}}},
+		{"outfield players never replace a goalkeeper", "picks.json", []int{1, 12}, nil, 44 - 6 + 13 - 4, 49, 10, []AutoSub{{5, 13}}},
+		{"substitutes keep three defenders", "picks.json", []int{2, 3}, nil, 44 - 2 - 1 + 13 - 4, 53, 10, []AutoSub{{2, 13}, {3, 14}}},
+		{"bench players without minutes stay on the bench", "picks.json", []int{13, 14, 15}, nil, 44 + 13 - 4, 53, 10, []AutoSub{}},
+		{"starters yet to play are not substituted", "picks.json", nil, []int{5}, 44 + 13 - 4, 53, 10, []AutoSub{}},
+		{"bench players yet to play hold up the substitution", "picks.json", nil, []int{13}, 44 + 13 - 4, 53, 10, []AutoSub{}},
+	}
+
+	for _, test := range tests {
+		var picks PicksResponse
+		readTestdata(t, test.picks, &picks)
+
+		score := scorePicks(picks, liveStats(t, test.blank, test.unplayed))
+
+		if score.CurrentPoints != test.current || score.ProjectedPoints != test.projected {
+			t.Errorf("scorePicks() %s points = %d projected %d, want %d projected %d",
+				test.scenario, score.CurrentPoints, score.ProjectedPoints, test.current, test.projected)
+		}
+
+		if !reflect.DeepEqual(score.AutoSubs, test.subs) {
+			t.Errorf("scorePicks() %s AutoSubs = %v, want %v", test.scenario, score.AutoSubs, test.subs)
+		}
+
+		armband := 0
+		for _, pick := range score.Picks {
+			if pick.Multiplier > 1 {
+				armband = pick.Element
+			}
+		}
+
+		if armband != test.armband {
+			t.Errorf("scorePicks() %s armband on %d, want %d", test.scenario, armband, test.armband)
+		}
+	}
+}
+
+func TestGetLive(t *testing.T) {
+	live, err := os.ReadFile("testdata/live.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	picks, err := os.ReadFile("testdata/picks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entry/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		response := mockFplResponse[0]
+		if r.PathValue("id") == "2" {
+			response = mockFplResponse[1]
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			panic(err)
+		}
+	})
+	mux.HandleFunc("GET /entry/{id}/event/{gw}/picks/", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "2" || r.PathValue("gw") != "99" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write(picks)
+	})
+	mux.HandleFunc("GET /event/99/live/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(live)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	savedPicks, savedLive := fplPicksURL, fplLiveURL
+	t.Cleanup(func() { fplPicksURL, fplLiveURL = savedPicks, savedLive })
+
+	fplURL = ts.URL + "/entry/%v/"
+	fplPicksURL = ts.URL + "/entry/%v/event/%d/picks/"
+	fplLiveURL = ts.URL + "/event/%d/live/"
+	resetCaches(t)
+
+	mockFixtures(t)
+
+	testResponse, err := getLive(context.Background(), []string{"1", "2"})
+	if err != nil {
+		t.Fatalf(`getLive("1, 2") err = (%v), want: nil err`, err)
+	}
+
+	// manager 2 has no picks
+	if testResponse.Status != StatusDegraded || len(testResponse.Errors) != 1 || testResponse.Errors[0].ID != "2" {
+		t.Errorf("getLive Status = %v Errors = %v, want (%v) with manager 2", testResponse.Status, testResponse.Errors, StatusDegraded)
+	}
+
+	if len(testResponse.League) != 1 {
+		t.Fatalf("getLive League = %v, want: only manager 1", testResponse.League)
+	}
+
+	// summary points of 55 replaced by the live score of 57 before the 4 point hit, already in the total of 77
+	entry := testResponse.League[0]
+	if entry.GwPoints != 57 || entry.Points != 77-55+57 || entry.CurrentPoints != 53 || entry.LeagueRank != 1 {
+		t.Errorf("getLive manager 1 gw_points = %d points = %d current_points = %d league_rank = %d, want 57, %d, 53, 1",
+			entry.GwPoints, entry.Points, entry.CurrentPoints, entry.LeagueRank, 77-55+57)
+	}
+
+	if entry.ProjectedPoints != 55 || len(entry.AutoSubs) != 1 {
+		t.Errorf("getLive manager 1 projected_points = %d auto_subs = %v, want 55 with 1 substitution", entry.ProjectedPoints, entry.AutoSubs)
+	}
+}
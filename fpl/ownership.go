@@ -0,0 +1,169 @@
+package fpl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+)
+
+type Player struct { // a player named from bootstrap-static
+	Element int    `json:"element"`
+	Name    string `json:"name"`
+	Club    string `json:"club"`
+}
+type PlayerOwnership struct { // how many managers own and captain a player
+	Player
+	Points    int     `json:"points"`    // the player's points for the gameweek
+	Owners    int     `json:"owners"`    // managers with the player in their squad
+	Ownership float64 `json:"ownership"` // percent of managers
+	Captains  int     `json:"captains"`
+}
+type Differential struct { // a player only one manager owns
+	Player
+	Points int `json:"points"` // earned for the manager, after their multiplier
+}
+type ManagerDifferentials struct { // a manager's differentials and the points they earned
+	ID            int            `json:"id"`
+	Name          string         `json:"name"`
+	Team          string         `json:"team"`
+	Points        int            `json:"points"`
+	Differentials []Differential `json:"differentials"`
+}
+type OwnershipResponse struct { // response with ownership of every player picked and each manager's differentials
+	LeagueName string                 `json:"league_name,omitempty"`
+	Gameweek   int                    `json:"gameweek"`
+	Timestamp  string                 `json:"timestamp"`
+	Status     string                 `json:"status"`
+	Players    []PlayerOwnership      `json:"players"`
+	Managers   []ManagerDifferentials `json:"managers"`
+	Errors     []ManagerError         `json:"errors"`
+}
+
+// Ownership writes player ownership and differentials for the gameweek in query parameter "gw",
+// or the current gameweek, as json
+func Ownership(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	gameweek, ok := requestGameweek(w, r)
+	if !ok {
+		return
+	}
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	ownershipResponse, err := getOwnership(r.Context(), managerList, gameweek)
+	if errors.Is(err, ErrGameweek) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	ownershipResponse.LeagueName = leagueName
+
+	writeJSON(w, ownershipResponse)
+}
+
+// getOwnership gets the managers' picks for the gameweek, 0 for the current gameweek, with player names from the reference data
+func getOwnership(ctx context.Context, managerList []string, gameweek int) (OwnershipResponse, error) {
+	gameweekPicks, err := getLeaguePicks(ctx, managerList, gameweek)
+	if err != nil {
+		return OwnershipResponse{}, err
+	}
+
+	names, err := reference.Players(ctx)
+	if err != nil {
+		return OwnershipResponse{}, err
+	}
+
+	players, managers := ownership(gameweekPicks.League, gameweekPicks.Picks, gameweekPicks.Live, names)
+
+	return OwnershipResponse{
+		Gameweek:  gameweekPicks.Gameweek,
+		Timestamp: time.Now().Format(timestampLayout),
+		Status:    gameweekPicks.Status,
+		Players:   players,
+		Managers:  managers,
+		Errors:    gameweekPicks.Errors,
+	}, nil
+}
+
+// ownership counts the owners and captains of every player in the managers' squads, most owned first,
+// and lists each manager's differentials: the players nobody else owns.
+func ownership(league []ManagerEntry, picks map[int]PicksResponse, live map[int]ElementStats, players map[int]Player) ([]PlayerOwnership, []ManagerDifferentials) {
+	owned := map[int]*PlayerOwnership{}
+
+	for _, entry := range league {
+		for _, pick := range picks[entry.ID].Picks {
+			player, ok := owned[pick.Element]
+			if !ok {
+				player = &PlayerOwnership{Player: playerName(players, pick.Element), Points: live[pick.Element].TotalPoints}
+				owned[pick.Element] = player
+			}
+
+			player.Owners++
+
+			if pick.IsCaptain {
+				player.Captains++
+			}
+		}
+	}
+
+	ownerships := make([]PlayerOwnership, 0, len(owned))
+	for _, player := range owned {
+		player.Ownership = float64(player.Owners*100) / float64(len(league))
+		ownerships = append(ownerships, *player)
+	}
+
+	slices.SortFunc(ownerships, func(a, b PlayerOwnership) int {
+		if c := cmp.Compare(b.Owners, a.Owners); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(b.Points, a.Points); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Element, b.Element)
+	})
+
+	managers := make([]ManagerDifferentials, 0, len(league))
+
+	for _, entry := range league {
+		manager := ManagerDifferentials{ID: entry.ID, Name: entry.Name, Team: entry.Team, Differentials: []Differential{}}
+
+		for _, pick := range scorePicks(picks[entry.ID], live).Picks {
+			if owned[pick.Element].Owners > 1 {
+				continue
+			}
+
+			points := pick.Points * pick.Multiplier
+			manager.Points += points
+			manager.Differentials = append(manager.Differentials, Differential{Player: playerName(players, pick.Element), Points: points})
+		}
+
+		managers = append(managers, manager)
+	}
+
+	return ownerships, managers
+}
+
+// players missing from bootstrap-static only have their element id
+func playerName(players map[int]Player, element int) Player {
+	if player, ok := players[element]; ok {
+		return player
+	}
+
+	return Player{Element: element}
+}
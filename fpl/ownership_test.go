@@ -0,0 +1,70 @@
+package fpl
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestOwnership(t *testing.T) {
+	var picks1, picks2 PicksResponse
+	readTestdata(t, "picks.json", &picks1)
+	readTestdata(t, "picks.json", &picks2)
+
+	// manager 2 has Salah instead of Isak and captains him
+	picks2.Picks = slices.Clone(picks2.Picks)
+	for i, pick := range picks2.Picks {
+		switch pick.Element {
+		case 10:
+			picks2.Picks[i].IsCaptain = false
+		case 11:
+			picks2.Picks[i].Element = 16
+			picks2.Picks[i].IsCaptain = true
+		}
+	}
+
+	names, err := fixtureReference(t).Players(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	league := []ManagerEntry{{ID: 1, Name: "first1 last1"}, {ID: 2, Name: "first2 last2"}}
+	players, managers := ownership(league, map[int]PicksResponse{1: picks1, 2: picks2}, liveStats(t, nil, nil), names)
+
+	if len(players) != 16 {
+		t.Errorf("ownership() = %d players, want 16", len(players))
+	}
+
+	// most owned, then most points
+	want := PlayerOwnership{Player: Player{Element: 10, Name: "Haaland", Club: "MCI"}, Points: 13, Owners: 2, Ownership: 100, Captains: 1}
+	if players[0] != want {
+		t.Errorf("ownership() first = %+v, want %+v", players[0], want)
+	}
+
+	for _, player := range players {
+		if player.Element == 16 && (player.Owners != 1 || player.Captains != 1 || player.Ownership != 50) {
+			t.Errorf("ownership() Salah = %+v, want 1 owner and captain", player)
+		}
+	}
+
+	wantManagers := []ManagerDifferentials{
+		{ID: 1, Name: "first1 last1", Points: 2, Differentials: []Differential{{Player{11, "Isak", "NEW"}, 2}}},
+		{ID: 2, Name: "first2 last2", Points: 10, Differentials: []Differential{{Player{16, "Salah", "LIV"}, 10}}},
+	}
+	if !reflect.DeepEqual(managers, wantManagers) {
+		t.Errorf("ownership() managers\ngot :%+v\nwant:%+v", managers, wantManagers)
+	}
+}
+
+func TestPlayerName(t *testing.T) {
+	players := map[int]Player{1: {Element: 1, Name: "Raya", Club: "ARS"}}
+
+	if got := playerName(players, 1); got.Name != "Raya" {
+		t.Errorf("playerName(1) = %+v, want Raya", got)
+	}
+
+	if got := playerName(players, 99); got != (Player{Element: 99}) {
+		t.Errorf("playerName(99) = %+v, want element 99 without a name", got)
+	}
+}
@@ -0,0 +1,100 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+type PicksResult struct { // result wrapper for PicksResponse, Error
+	ManagerID string
+	Picks     PicksResponse
+	Error     error
+}
+
+func (result PicksResult) managerError() (string, error) { return result.ManagerID, result.Error }
+
+// every manager's picks for a gameweek with the gameweek's live stats
+type leaguePicks struct {
+	LeagueResponse                       // managers with picks, the others are in Errors
+	Picks          map[int]PicksResponse // by manager id
+	Live           map[int]ElementStats  // by element id
+}
+
+// requestGameweek returns the gameweek in query parameter "gw", 0 when it is not set.
+// An invalid gameweek is written to w as a bad request and ok is false.
+func requestGameweek(w http.ResponseWriter, r *http.Request) (gameweek int, ok bool) {
+	gw := r.URL.Query().Get("gw")
+	if gw == "" {
+		return 0, true
+	}
+
+	gameweek, err := strconv.Atoi(gw)
+	if err != nil || gameweek < 1 {
+		http.Error(w, "query parameter -gw- must be a gameweek number", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return gameweek, true
+}
+
+// getLeaguePicks gets the managers from getData then their picks for the gameweek, 0 for the current gameweek.
+// Managers without picks are added to the errors, it only fails when no manager has picks or the client has gone.
+func getLeaguePicks(ctx context.Context, managerList []string, gameweek int) (leaguePicks, error) {
+	leagueResponse, err := getData(ctx, managerList)
+	if err != nil {
+		return leaguePicks{}, err
+	}
+
+	if gameweek == 0 {
+		gameweek = leagueResponse.Gameweek
+	}
+
+	if gameweek > leagueResponse.Gameweek {
+		return leaguePicks{}, fmt.Errorf("gameweek %d %w, the current gameweek is %d", gameweek, ErrGameweek, leagueResponse.Gameweek)
+	}
+
+	live, err := getLiveStats(ctx, gameweek)
+	if err != nil {
+		return leaguePicks{}, err
+	}
+
+	ids := make([]string, len(leagueResponse.League))
+	for i, entry := range leagueResponse.League {
+		ids[i] = strconv.Itoa(entry.ID)
+	}
+
+	results, err := fetchAll(ctx, ids, func(ctx context.Context, manager string) PicksResult {
+		picks, err := getPicks(ctx, manager, gameweek)
+		return PicksResult{ManagerID: manager, Picks: picks, Error: err}
+	})
+	if err != nil {
+		return leaguePicks{}, err
+	}
+
+	gameweekPicks := leaguePicks{
+		LeagueResponse: leagueResponse,
+		Picks:          map[int]PicksResponse{},
+		Live:           live,
+	}
+	gameweekPicks.Gameweek = gameweek
+	gameweekPicks.League = []ManagerEntry{}
+
+	retrieved, managerErrors, err := collectResults(results)
+	if err != nil {
+		return leaguePicks{}, err
+	}
+
+	gameweekPicks.Errors = slices.Concat(leagueResponse.Errors, managerErrors)
+	gameweekPicks.Status = responseStatus(gameweekPicks.Errors)
+
+	for _, i := range retrieved {
+		entry := leagueResponse.League[i]
+		gameweekPicks.League = append(gameweekPicks.League, entry)
+		gameweekPicks.Picks[entry.ID] = results[i].Picks
+	}
+
+	return gameweekPicks, nil
+}
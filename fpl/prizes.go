@@ -0,0 +1,388 @@
+package fpl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// how prizes won by level managers are settled
+const (
+	TiesSplit    = "split"    // the prize is shared equally, the default
+	TiesRollover = "rollover" // the prize is added to the next prize of the same kind, the last ones are split
+)
+
+// prize kinds
+const (
+	PrizeGameweek = "gameweek"
+	PrizeMonth    = "month"
+	PrizeOverall  = "overall"
+)
+
+// ErrNoPrizes is returned for a league without prize rules
+var ErrNoPrizes = errors.New("league has no prizes configured")
+
+type PrizeRules struct { // amounts paid for each gameweek, month and overall place, from the leagues file
+	Gameweek float64   `json:"gameweek"`
+	Month    float64   `json:"month"`
+	Overall  []float64 `json:"overall"` // 1st, 2nd, ...
+	Ties     string    `json:"ties"`
+}
+type Prize struct { // a prize and the managers who won it
+	Prize      string        `json:"prize"`
+	Kind       string        `json:"kind"`
+	Points     int           `json:"points"`
+	Amount     float64       `json:"amount"`
+	Share      float64       `json:"share"` // paid to each winner
+	RolledOver bool          `json:"rolled_over"`
+	Winners    []AwardWinner `json:"winners"`
+}
+type LedgerEntry struct { // what a manager has won so far
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Team   string   `json:"team"`
+	Won    float64  `json:"won"`
+	Prizes []string `json:"prizes"`
+}
+type LedgerResponse struct { // response with every prize settled so far and the running ledger
+	LeagueName  string         `json:"league_name,omitempty"`
+	Timestamp   string         `json:"timestamp"`
+	Status      string         `json:"status"`
+	Provisional bool           `json:"provisional"` // settled without every manager, prizes may change
+	Prizes      []Prize        `json:"prizes"`
+	Ledger      []LedgerEntry  `json:"ledger"`
+	Errors      []ManagerError `json:"errors"`
+}
+
+func (rules PrizeRules) validate() error {
+	if rules.Ties != "" && rules.Ties != TiesSplit && rules.Ties != TiesRollover {
+		return fmt.Errorf("ties %q must be %q or %q", rules.Ties, TiesSplit, TiesRollover)
+	}
+
+	if rules.Gameweek < 0 || rules.Month < 0 || slices.ContainsFunc(rules.Overall, func(amount float64) bool { return amount < 0 }) {
+		return errors.New("prize amounts can not be negative")
+	}
+
+	return nil
+}
+
+// PrizesJSON writes the prize ledger of the league in path value "league" as json
+func PrizesJSON(w http.ResponseWriter, r *http.Request) {
+	ledger, ok := requestLedger(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, ledger)
+}
+
+// PrizesTable displays the prize ledger of the league in path value "league" as html
+func PrizesTable(w http.ResponseWriter, r *http.Request) {
+	ledger, ok := requestLedger(w, r)
+	if !ok {
+		return
+	}
+
+	prizesTemplate := template.Must(template.ParseFiles("fpl/PrizesTemplate.html"))
+	if err := prizesTemplate.Execute(w, ledger); err != nil {
+		log.Printf("error executing prizesTemplate: %v", err)
+	}
+}
+
+// build the ledger for the request, errors are written to w and ok is false
+func requestLedger(w http.ResponseWriter, r *http.Request) (LedgerResponse, bool) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	league, err := findLeague(r.PathValue("league"))
+	if err == nil && league.Prizes == nil {
+		err = fmt.Errorf("%q %w", league.Slug, ErrNoPrizes)
+	}
+
+	if errors.Is(err, ErrUnknownLeague) || errors.Is(err, ErrNoPrizes) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return LedgerResponse{}, false
+	}
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "%+v\n", err)
+
+		return LedgerResponse{}, false
+	}
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return LedgerResponse{}, false
+	}
+
+	ledger, err := getLedger(r.Context(), managerList, *league.Prizes)
+	if err != nil {
+		writeDataError(w, err)
+
+		return LedgerResponse{}, false
+	}
+
+	ledger.LeagueName = leagueName
+
+	return ledger, true
+}
+
+// getLedger settles the prizes from each manager's history and the gameweeks and months in the reference data.
+// When some managers could not be retrieved the ledger is provisional, as they may have won a prize.
+func getLedger(ctx context.Context, managerList []string, rules PrizeRules) (LedgerResponse, error) {
+	historyResponse, err := getHistories(ctx, managerList)
+	if err != nil {
+		return LedgerResponse{}, err
+	}
+
+	bootstrap, err := reference.Bootstrap(ctx)
+	if err != nil {
+		return LedgerResponse{}, err
+	}
+
+	prizes := settlePrizes(historyResponse.Managers, bootstrap, rules)
+
+	return LedgerResponse{
+		Timestamp:   time.Now().Format(timestampLayout),
+		Status:      historyResponse.Status,
+		Provisional: historyResponse.Status == StatusDegraded,
+		Prizes:      prizes,
+		Ledger:      buildLedger(historyResponse.Managers, prizes),
+		Errors:      historyResponse.Errors,
+	}, nil
+}
+
+// settlePrizes pays the gameweek prize for every finished gameweek, the month prize for every month whose
+// last gameweek has finished, and the overall places once the season has finished.
+// Gameweek and month scores are after transfer hits. A tie in the last gameweek or month of the season has
+// no next prize to roll over to, so it is split.
+func settlePrizes(managers []ManagerHistory, bootstrap BootstrapResponse, rules PrizeRules) []Prize {
+	prizes := []Prize{}
+
+	// points after hits for each manager in each gameweek they played
+	points := make([]map[int]int, len(managers))
+	for i, manager := range managers {
+		points[i] = map[int]int{}
+		for _, gw := range manager.Gameweeks {
+			points[i][gw.Gameweek] = gw.Points - gw.TransfersCost
+		}
+	}
+
+	finished := map[int]bool{}
+	for _, event := range bootstrap.Events {
+		finished[event.ID] = event.Finished
+	}
+
+	// score returns the managers' points over the gameweeks, managers who did not play them are left out
+	score := func(start, stop int) map[int]int {
+		scores := map[int]int{}
+
+		for i := range managers {
+			for gw := start; gw <= stop; gw++ {
+				if p, ok := points[i][gw]; ok {
+					scores[i] += p
+				}
+			}
+		}
+
+		return scores
+	}
+
+	// ties returns how a tie is settled, the last prize of a kind can not roll over
+	ties := func(last bool) string {
+		if last {
+			return TiesSplit
+		}
+
+		return rules.Ties
+	}
+
+	if rules.Gameweek > 0 {
+		pot := 0.0
+
+		for i, event := range bootstrap.Events {
+			if !event.Finished {
+				continue
+			}
+
+			pot += rules.Gameweek
+			last := i == len(bootstrap.Events)-1
+			prize := payPrize(fmt.Sprintf("Gameweek %d", event.ID), PrizeGameweek, pot, score(event.ID, event.ID), managers, ties(last))
+			prizes = append(prizes, prize)
+
+			if !prize.RolledOver {
+				pot = 0
+			}
+		}
+	}
+
+	if rules.Month > 0 {
+		pot := 0.0
+
+		lastMonth := 0
+		for _, phase := range bootstrap.Phases {
+			if phase.ID != overallPhase {
+				lastMonth = max(lastMonth, phase.StopEvent)
+			}
+		}
+
+		for _, phase := range bootstrap.Phases {
+			if phase.ID == overallPhase || !finished[phase.StopEvent] {
+				continue
+			}
+
+			pot += rules.Month
+			prize := payPrize(phase.Name, PrizeMonth, pot, score(phase.StartEvent, phase.StopEvent), managers, ties(phase.StopEvent == lastMonth))
+			prizes = append(prizes, prize)
+
+			if !prize.RolledOver {
+				pot = 0
+			}
+		}
+	}
+
+	if len(bootstrap.Events) > 0 && bootstrap.Events[len(bootstrap.Events)-1].Finished {
+		prizes = append(prizes, placePrizes(managers, rules.Overall)...)
+	}
+
+	return prizes
+}
+
+// payPrize gives the pot to the managers with the highest score, level managers split it or roll it over
+func payPrize(name, kind string, pot float64, scores map[int]int, managers []ManagerHistory, ties string) Prize {
+	prize := Prize{Prize: name, Kind: kind, Amount: pot, Winners: []AwardWinner{}}
+
+	if len(scores) == 0 {
+		prize.RolledOver = ties == TiesRollover
+		return prize
+	}
+
+	prize.Points = math.MinInt
+
+	for i := range managers {
+		s, ok := scores[i]
+		if !ok {
+			continue
+		}
+
+		if s > prize.Points {
+			prize.Points = s
+			prize.Winners = prize.Winners[:0]
+		}
+
+		if s == prize.Points {
+			prize.Winners = append(prize.Winners, AwardWinner{ID: managers[i].ID, Name: managers[i].Name, Team: managers[i].Team})
+		}
+	}
+
+	if len(prize.Winners) > 1 && ties == TiesRollover {
+		prize.RolledOver = true
+		return prize
+	}
+
+	prize.Share = roundPence(pot / float64(len(prize.Winners)))
+
+	return prize
+}
+
+// placePrizes pays the overall places on final total points, level managers split the prizes for the places they share
+func placePrizes(managers []ManagerHistory, amounts []float64) []Prize {
+	type total struct {
+		index, points int
+	}
+
+	totals := []total{}
+
+	for i, manager := range managers {
+		if n := len(manager.Gameweeks); n > 0 {
+			totals = append(totals, total{i, manager.Gameweeks[n-1].TotalPoints})
+		}
+	}
+
+	slices.SortStableFunc(totals, func(a, b total) int { return cmp.Compare(b.points, a.points) })
+
+	prizes := []Prize{}
+
+	for place := 0; place < len(totals) && place < len(amounts); {
+		level := 1
+		for place+level < len(totals) && totals[place+level].points == totals[place].points {
+			level++
+		}
+
+		pot := 0.0
+		for _, amount := range amounts[place:min(place+level, len(amounts))] {
+			pot += amount
+		}
+
+		name := "Overall " + ordinal(place+1)
+		if level > 1 {
+			name += "="
+		}
+
+		prize := Prize{Prize: name, Kind: PrizeOverall, Points: totals[place].points, Amount: pot, Share: roundPence(pot / float64(level))}
+		for _, t := range totals[place : place+level] {
+			prize.Winners = append(prize.Winners, AwardWinner{ID: managers[t.index].ID, Name: managers[t.index].Name, Team: managers[t.index].Team})
+		}
+
+		prizes = append(prizes, prize)
+		place += level
+	}
+
+	return prizes
+}
+
+// buildLedger totals each manager's winnings, biggest winners first
+func buildLedger(managers []ManagerHistory, prizes []Prize) []LedgerEntry {
+	ledger := make([]LedgerEntry, len(managers))
+	index := map[int]int{}
+
+	for i, manager := range managers {
+		ledger[i] = LedgerEntry{ID: manager.ID, Name: manager.Name, Team: manager.Team, Prizes: []string{}}
+		index[manager.ID] = i
+	}
+
+	for _, prize := range prizes {
+		if prize.RolledOver {
+			continue
+		}
+
+		for _, winner := range prize.Winners {
+			entry := &ledger[index[winner.ID]]
+			entry.Won = roundPence(entry.Won + prize.Share)
+			entry.Prizes = append(entry.Prizes, prize.Prize)
+		}
+	}
+
+	slices.SortStableFunc(ledger, func(a, b LedgerEntry) int { return cmp.Compare(b.Won, a.Won) })
+
+	return ledger
+}
+
+func roundPence(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// 1st, 2nd, 3rd, 4th, ... 11th, 12th, 13th, ... 21st
+func ordinal(n int) string {
+	suffix := "th"
+
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
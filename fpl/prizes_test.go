@@ -0,0 +1,224 @@
+package fpl
+
+import (
+	"html/template"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// history builds a manager's history from their points before hits and hits for each gameweek from start
+func history(id, start int, points, hits []int) ManagerHistory {
+	manager := ManagerHistory{ID: id, Name: "manager" + ordinal(id)}
+	total := 0
+
+	for i := range points {
+		total += points[i] - hits[i]
+		manager.Gameweeks = append(manager.Gameweeks, GameweekHistory{
+			Gameweek:      start + i,
+			Points:        points[i],
+			TransfersCost: hits[i],
+			TotalPoints:   total,
+		})
+	}
+
+	return manager
+}
+
+// prize results as name: share, winner ids
+type settled map[string]struct {
+	Share   float64
+	Winners []int
+}
+
+func settledPrizes(prizes []Prize) settled {
+	results := settled{}
+
+	for _, prize := range prizes {
+		var ids []int
+		for _, winner := range prize.Winners {
+			ids = append(ids, winner.ID)
+		}
+
+		results[prize.Prize] = struct {
+			Share   float64
+			Winners []int
+		}{prize.Share, ids}
+	}
+
+	return results
+}
+
+func TestSettlePrizes(t *testing.T) {
+	// gameweeks 1 to 3 and August have finished, manager 3 joined in gameweek 2
+	managers := []ManagerHistory{
+		history(1, 1, []int{60, 50, 70}, []int{0, 4, 0}),
+		history(2, 1, []int{60, 40, 80}, []int{0, 0, 0}),
+		history(3, 2, []int{45, 70}, []int{0, 0}),
+	}
+
+	var bootstrap BootstrapResponse
+	readTestdata(t, "bootstrap.json", &bootstrap)
+
+	tests := []struct {
+		scenario string
+		ties     string
+		want     settled
+		ledger   map[int]float64
+	}{
+		{
+			scenario: "split ties",
+			ties:     TiesSplit,
+			want: settled{
+				"Gameweek 1": {2.5, []int{1, 2}},
+				"Gameweek 2": {5, []int{1}},
+				"Gameweek 3": {5, []int{2}},
+				"August":     {20, []int{2}},
+			},
+			ledger: map[int]float64{1: 7.5, 2: 27.5, 3: 0},
+		},
+		{
+			scenario: "rollover ties",
+			ties:     TiesRollover,
+			want: settled{
+				"Gameweek 1": {0, []int{1, 2}},
+				"Gameweek 2": {10, []int{1}},
+				"Gameweek 3": {5, []int{2}},
+				"August":     {20, []int{2}},
+			},
+			ledger: map[int]float64{1: 10, 2: 25, 3: 0},
+		},
+	}
+
+	for _, test := range tests {
+		rules := PrizeRules{Gameweek: 5, Month: 20, Overall: []float64{100, 50}, Ties: test.ties}
+		prizes := settlePrizes(managers, bootstrap, rules)
+
+		if got := settledPrizes(prizes); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("settlePrizes() %s\ngot :%v\nwant:%v", test.scenario, got, test.want)
+		}
+
+		ledger := buildLedger(managers, prizes)
+		for _, entry := range ledger {
+			if entry.Won != test.ledger[entry.ID] {
+				t.Errorf("buildLedger() %s manager %d won %.2f, want %.2f", test.scenario, entry.ID, entry.Won, test.ledger[entry.ID])
+			}
+		}
+
+		if ledger[0].ID != 2 {
+			t.Errorf("buildLedger() %s first = manager %d, want biggest winner 2", test.scenario, ledger[0].ID)
+		}
+	}
+}
+
+func TestSettlePrizesOverall(t *testing.T) {
+	// managers 1 and 2 finish level on 180
+	managers := []ManagerHistory{
+		history(1, 1, []int{60, 50, 70}, []int{0, 0, 0}),
+		history(2, 1, []int{60, 40, 80}, []int{0, 0, 0}),
+		history(3, 1, []int{30, 45, 70}, []int{0, 0, 0}),
+	}
+
+	bootstrap := BootstrapResponse{Events: []Event{{ID: 1, Finished: true}, {ID: 2, Finished: true}, {ID: 3, Finished: true}}}
+
+	want := settled{
+		"Overall 1st=": {75, []int{1, 2}},
+		"Overall 3rd":  {25, []int{3}},
+	}
+
+	prizes := settlePrizes(managers, bootstrap, PrizeRules{Overall: []float64{100, 50, 25}})
+	if got := settledPrizes(prizes); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlePrizes() overall\ngot :%v\nwant:%v", got, want)
+	}
+}
+
+func TestSettlePrizesLastRollover(t *testing.T) {
+	// managers 1 and 2 are level in gameweeks 1 and 3 and in September, the last month
+	managers := []ManagerHistory{
+		history(1, 1, []int{60, 50, 70}, []int{0, 0, 0}),
+		history(2, 1, []int{60, 40, 70}, []int{0, 0, 0}),
+	}
+
+	bootstrap := BootstrapResponse{
+		Events: []Event{{ID: 1, Finished: true}, {ID: 2, Finished: true}, {ID: 3, Finished: true}},
+		Phases: []Phase{
+			{ID: overallPhase, Name: "Overall", StartEvent: 1, StopEvent: 3},
+			{ID: 2, Name: "August", StartEvent: 1, StopEvent: 2},
+			{ID: 3, Name: "September", StartEvent: 3, StopEvent: 3},
+		},
+	}
+
+	want := settled{
+		"Gameweek 1": {0, []int{1, 2}},
+		"Gameweek 2": {10, []int{1}},
+		"Gameweek 3": {2.5, []int{1, 2}},
+		"August":     {20, []int{1}},
+		"September":  {10, []int{1, 2}},
+	}
+
+	prizes := settlePrizes(managers, bootstrap, PrizeRules{Gameweek: 5, Month: 20, Ties: TiesRollover})
+	if got := settledPrizes(prizes); !reflect.DeepEqual(got, want) {
+		t.Errorf("settlePrizes() last rollover\ngot :%v\nwant:%v", got, want)
+	}
+
+	for _, prize := range prizes {
+		if prize.RolledOver && (prize.Prize == "Gameweek 3" || prize.Prize == "September") {
+			t.Errorf("settlePrizes() %s rolled over, want the last pot split", prize.Prize)
+		}
+	}
+}
+
+func TestPrizeRulesValidate(t *testing.T) {
+	tests := []struct {
+		rules PrizeRules
+		valid bool
+	}{
+		{PrizeRules{Gameweek: 5, Overall: []float64{100}}, true},
+		{PrizeRules{Month: 20, Ties: TiesRollover}, true},
+		{PrizeRules{Ties: "coin toss"}, false},
+		{PrizeRules{Overall: []float64{100, -50}}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.rules.validate(); (err == nil) != test.valid {
+			t.Errorf("validate(%+v) err = (%v), want valid %v", test.rules, err, test.valid)
+		}
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	want := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd"}
+
+	for n, s := range want {
+		if got := ordinal(n); got != s {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, s)
+		}
+	}
+}
+
+func TestPrizesTemplate(t *testing.T) {
+	prizesTemplate := template.Must(template.ParseFiles("PrizesTemplate.html"))
+
+	ledger := LedgerResponse{
+		LeagueName:  "Pub League",
+		Provisional: true,
+		Prizes: []Prize{
+			{Prize: "Gameweek 1", Amount: 5, Share: 2.5, Winners: []AwardWinner{{ID: 1, Name: "first1 last1"}, {ID: 2, Name: "first2 last2"}}},
+			{Prize: "Gameweek 2", Amount: 5, RolledOver: true, Winners: []AwardWinner{{ID: 1, Name: "first1 last1"}, {ID: 2, Name: "first2 last2"}}},
+		},
+		Ledger: []LedgerEntry{{ID: 1, Name: "first1 last1", Won: 2.5, Prizes: []string{"Gameweek 1"}}},
+		Errors: []ManagerError{{ID: "3", Status: http.StatusNotFound, Error: "not found"}},
+	}
+
+	var page strings.Builder
+	if err := prizesTemplate.Execute(&page, ledger); err != nil {
+		t.Fatalf("Execute() err = (%v), want: nil err", err)
+	}
+
+	for _, want := range []string{"Pub League FPL Prizes", "first1 last1, first2 last2 (2.50 each)", "(rolled over)", "<td>2.50</td>", "Provisional", "3: not found"} {
+		if !strings.Contains(page.String(), want) {
+			t.Errorf("prizes page does not contain %q\n%s", want, page.String())
+		}
+	}
+}
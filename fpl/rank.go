@@ -0,0 +1,116 @@
+package fpl
+
+import (
+	"cmp"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+// tie-breaks applied in order to managers level on total points, set with environment variable "tiebreaks"
+const defaultTieBreaks = "gw_points,overall_rank"
+
+// a tieBreak compares two managers, negative when a ranks above b
+type tieBreak func(a, b ManagerEntry) int
+
+var tieBreaks = map[string]tieBreak{
+	"gw_points": func(a, b ManagerEntry) int {
+		return cmp.Compare(b.GwPoints, a.GwPoints)
+	},
+	"overall_rank": func(a, b ManagerEntry) int {
+		return compareRanks(a.Rank, b.Rank)
+	},
+}
+
+// lower ranks are better, 0 is unranked so comes last
+func compareRanks(a, b int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == 0:
+		return 1
+	case b == 0:
+		return -1
+	}
+
+	return cmp.Compare(a, b)
+}
+
+// getTieBreaks returns the tie-breaks named in environment variable "tiebreaks", unknown names are ignored
+func getTieBreaks() []tieBreak {
+	names, ok := os.LookupEnv("tiebreaks")
+	if !ok {
+		names = defaultTieBreaks
+	}
+
+	var order []tieBreak
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		tb, ok := tieBreaks[name]
+		if !ok {
+			log.Printf("unknown tie-break %q in environment variable -tiebreaks-", name)
+			continue
+		}
+
+		order = append(order, tb)
+	}
+
+	return order
+}
+
+// rankLeague sorts the league by total points then the tie-breaks, and sets each manager's league rank
+// and their rank on last gameweek's totals. Managers still level share a rank and are ordered by id.
+func rankLeague(league []ManagerEntry, order []tieBreak) {
+	byTotal := func(a, b ManagerEntry) int {
+		if c := cmp.Compare(b.Points, a.Points); c != 0 {
+			return c
+		}
+
+		for _, tb := range order {
+			if c := tb(a, b); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	}
+
+	// previous totals have no gameweek points or rank to split them, gameweek points are before the
+	// transfer hits already taken from the total
+	previousTotal := func(entry ManagerEntry) int {
+		return entry.Points - (entry.GwPoints - entry.GwTransfersCost)
+	}
+	byPreviousTotal := func(a, b ManagerEntry) int {
+		return cmp.Compare(previousTotal(b), previousTotal(a))
+	}
+
+	setRanks(league, byPreviousTotal, func(entry *ManagerEntry, rank int) { entry.PreviousLeagueRank = rank })
+	setRanks(league, byTotal, func(entry *ManagerEntry, rank int) { entry.LeagueRank = rank })
+}
+
+// sort league with compare, falling back to id, and set ranks shared by managers that compare equal
+func setRanks(league []ManagerEntry, compare func(a, b ManagerEntry) int, set func(entry *ManagerEntry, rank int)) {
+	slices.SortFunc(league, func(a, b ManagerEntry) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	rank := 0
+
+	for i := range league {
+		if i == 0 || compare(league[i-1], league[i]) != 0 {
+			rank = i + 1
+		}
+
+		set(&league[i], rank)
+	}
+}
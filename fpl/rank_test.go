@@ -0,0 +1,131 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type rankResult struct {
+	ID, LeagueRank, PreviousLeagueRank int
+}
+
+func ranks(league []ManagerEntry) []rankResult {
+	var results []rankResult
+	for _, entry := range league {
+		results = append(results, rankResult{entry.ID, entry.LeagueRank, entry.PreviousLeagueRank})
+	}
+
+	return results
+}
+
+func TestRankLeague(t *testing.T) {
+	league := []ManagerEntry{
+		{ID: 5, Points: 500, GwPoints: 50, Rank: 3000},
+		{ID: 1, Points: 520, GwPoints: 40, Rank: 1000},
+		{ID: 4, Points: 500, GwPoints: 50, Rank: 2000},
+		{ID: 2, Points: 500, GwPoints: 70, Rank: 9000},
+		{ID: 3, Points: 480, GwPoints: 90, Rank: 0},
+		{ID: 6, Points: 480, GwPoints: 90, Rank: 0},
+	}
+
+	tests := []struct {
+		scenario string
+		order    []tieBreak
+		want     []rankResult
+	}{
+		{
+			scenario: "gw points then overall rank",
+			order:    []tieBreak{tieBreaks["gw_points"], tieBreaks["overall_rank"]},
+			want:     []rankResult{{1, 1, 1}, {2, 2, 4}, {4, 3, 2}, {5, 4, 2}, {3, 5, 5}, {6, 5, 5}},
+		},
+		{
+			scenario: "overall rank only",
+			order:    []tieBreak{tieBreaks["overall_rank"]},
+			want:     []rankResult{{1, 1, 1}, {4, 2, 2}, {5, 3, 2}, {2, 4, 4}, {3, 5, 5}, {6, 5, 5}},
+		},
+		{
+			scenario: "no tie-breaks",
+			order:    nil,
+			want:     []rankResult{{1, 1, 1}, {2, 2, 4}, {4, 2, 2}, {5, 2, 2}, {3, 5, 5}, {6, 5, 5}},
+		},
+	}
+
+	for _, test := range tests {
+		// shuffled input gives the same order every time
+		for _, start := range []int{0, 2, 5} {
+			shuffled := append(append([]ManagerEntry{}, league[start:]...), league[:start]...)
+			rankLeague(shuffled, test.order)
+
+			if got := ranks(shuffled); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("rankLeague() %s\ngot :%v\nwant:%v", test.scenario, got, test.want)
+			}
+		}
+	}
+}
+
+func TestRankLeagueTransferHit(t *testing.T) {
+	// manager 1 had 74 points before scoring 30 less a 4 point hit, manager 2 had 72
+	league := []ManagerEntry{
+		{ID: 1, Points: 100, GwPoints: 30, GwTransfersCost: 4},
+		{ID: 2, Points: 92, GwPoints: 20},
+	}
+
+	rankLeague(league, nil)
+
+	if want := []rankResult{{1, 1, 1}, {2, 2, 2}}; !reflect.DeepEqual(ranks(league), want) {
+		t.Errorf("rankLeague() with a transfer hit = %v, want %v", ranks(league), want)
+	}
+}
+
+func TestGetTieBreaks(t *testing.T) {
+	t.Setenv("tiebreaks", "overall_rank, unknown,gw_points")
+
+	if got := len(getTieBreaks()); got != 2 {
+		t.Errorf("getTieBreaks() = %d tie-breaks, want 2", got)
+	}
+
+	t.Setenv("tiebreaks", "")
+
+	if got := len(getTieBreaks()); got != 0 {
+		t.Errorf("getTieBreaks() empty = %d tie-breaks, want 0", got)
+	}
+}
+
+func TestGetDataRanked(t *testing.T) {
+	ts := setTestServer()
+	defer ts.Close()
+
+	// manager 2 took a 4 point hit this gameweek, manager 1 has no picks
+	picks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/entry/2/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		fmt.Fprint(w, `{"entry_history": {"event_transfers_cost": 4}}`)
+	}))
+	defer picks.Close()
+
+	savedPicks := fplPicksURL
+	t.Cleanup(func() { fplPicksURL = savedPicks })
+
+	fplURL = ts.URL + EntryPlaceholder
+	fplPicksURL = picks.URL + "/entry/%v/event/%d/picks/"
+	resetCaches(t)
+
+	testResponse, err := getData(context.Background(), []string{"1", "2"})
+	if err != nil {
+		t.Fatalf(`getData("1, 2") err = (%v), want: nil err`, err)
+	}
+
+	// manager 2 has more points, and had 26 points before this gameweek to manager 1's 22
+	want := []rankResult{{2, 1, 1}, {1, 2, 2}}
+	if got := ranks(testResponse.League); !reflect.DeepEqual(got, want) {
+		t.Errorf("getData league ranks = %v, want %v", got, want)
+	}
+}
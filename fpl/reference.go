@@ -0,0 +1,244 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	referenceTTL        = time.Hour       // longest bootstrap-static is reused, for prices and finished gameweeks
+	referenceRetryDelay = 5 * time.Minute // stale data is reused this long after a failed refresh
+)
+
+// ErrNotFound is returned for an id that is not in bootstrap-static
+var ErrNotFound = errors.New("not found in bootstrap-static")
+
+// bootstrap-static with lookups by id
+type referenceData struct {
+	bootstrap BootstrapResponse
+	elements  map[int]Element
+	clubs     map[int]Club
+	positions map[int]Position
+	players   map[int]Player
+}
+
+// A Reference serves bootstrap-static data, fetched once and reused until the next gameweek deadline or
+// referenceTTL. A refresh is shared by concurrent callers and stale data is served if it fails.
+type Reference struct {
+	load func(ctx context.Context) (BootstrapResponse, error)
+	now  func() time.Time
+
+	mu         sync.Mutex
+	data       *referenceData
+	expires    time.Time
+	err        error         // from the last refresh
+	refreshing chan struct{} // closed when the refresh in progress finishes
+}
+
+// the reference data used by every handler, replaced in tests
+var reference = NewReference(getBootstrap)
+
+// NewReference returns a Reference that gets bootstrap-static with load
+func NewReference(load func(ctx context.Context) (BootstrapResponse, error)) *Reference {
+	return &Reference{load: load, now: time.Now}
+}
+
+// get returns the cached data, waiting for a refresh when it has expired. The refresh is not cancelled
+// with ctx as other callers may be waiting for it.
+func (r *Reference) get(ctx context.Context) (*referenceData, error) {
+	r.mu.Lock()
+
+	if r.data != nil && r.now().Before(r.expires) {
+		data := r.data
+		r.mu.Unlock()
+
+		return data, nil
+	}
+
+	if r.refreshing == nil {
+		r.refreshing = make(chan struct{})
+		go r.refresh(r.refreshing)
+	}
+
+	done := r.refreshing
+	r.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.data == nil {
+		return nil, r.err
+	}
+
+	return r.data, nil
+}
+
+func (r *Reference) refresh(done chan struct{}) {
+	bootstrap, err := r.load(context.Background())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer close(done)
+
+	r.refreshing = nil
+	r.err = err
+	now := r.now()
+
+	if err != nil {
+		r.expires = now.Add(referenceRetryDelay)
+		return
+	}
+
+	r.data = newReferenceData(bootstrap)
+	r.expires = now.Add(referenceTTL)
+
+	// the current gameweek changes at the next deadline
+	for _, event := range bootstrap.Events {
+		if event.DeadlineTime.After(now) {
+			r.expires = minTime(r.expires, event.DeadlineTime)
+			break
+		}
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
+
+func newReferenceData(bootstrap BootstrapResponse) *referenceData {
+	data := &referenceData{
+		bootstrap: bootstrap,
+		elements:  make(map[int]Element, len(bootstrap.Elements)),
+		clubs:     make(map[int]Club, len(bootstrap.Teams)),
+		positions: make(map[int]Position, len(bootstrap.ElementTypes)),
+		players:   make(map[int]Player, len(bootstrap.Elements)),
+	}
+
+	for _, club := range bootstrap.Teams {
+		data.clubs[club.ID] = club
+	}
+
+	for _, position := range bootstrap.ElementTypes {
+		data.positions[position.ID] = position
+	}
+
+	for _, element := range bootstrap.Elements {
+		data.elements[element.ID] = element
+		data.players[element.ID] = Player{Element: element.ID, Name: element.WebName, Club: data.clubs[element.Team].ShortName}
+	}
+
+	return data
+}
+
+// Bootstrap returns the whole of bootstrap-static
+func (r *Reference) Bootstrap(ctx context.Context) (BootstrapResponse, error) {
+	data, err := r.get(ctx)
+	if err != nil {
+		return BootstrapResponse{}, err
+	}
+
+	return data.bootstrap, nil
+}
+
+// Players returns the name and club short name of every player by element id
+func (r *Reference) Players(ctx context.Context) (map[int]Player, error) {
+	data, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.players, nil
+}
+
+// Player returns the player with the element id
+func (r *Reference) Player(ctx context.Context, id int) (Element, error) {
+	data, err := r.get(ctx)
+	if err != nil {
+		return Element{}, err
+	}
+
+	element, ok := data.elements[id]
+	if !ok {
+		return Element{}, fmt.Errorf("player %d %w", id, ErrNotFound)
+	}
+
+	return element, nil
+}
+
+// Team returns the Premier League team with the id
+func (r *Reference) Team(ctx context.Context, id int) (Club, error) {
+	data, err := r.get(ctx)
+	if err != nil {
+		return Club{}, err
+	}
+
+	club, ok := data.clubs[id]
+	if !ok {
+		return Club{}, fmt.Errorf("team %d %w", id, ErrNotFound)
+	}
+
+	return club, nil
+}
+
+// Position returns the element type with the id
+func (r *Reference) Position(ctx context.Context, id int) (Position, error) {
+	data, err := r.get(ctx)
+	if err != nil {
+		return Position{}, err
+	}
+
+	position, ok := data.positions[id]
+	if !ok {
+		return Position{}, fmt.Errorf("position %d %w", id, ErrNotFound)
+	}
+
+	return position, nil
+}
+
+// CurrentEvent returns the gameweek in progress, or most recently started
+func (r *Reference) CurrentEvent(ctx context.Context) (Event, error) {
+	return r.event(ctx, "current", func(event Event) bool { return event.IsCurrent })
+}
+
+// NextEvent returns the gameweek with the next deadline
+func (r *Reference) NextEvent(ctx context.Context) (Event, error) {
+	return r.event(ctx, "next", func(event Event) bool { return event.IsNext })
+}
+
+// Deadline returns the deadline of the gameweek
+func (r *Reference) Deadline(ctx context.Context, gameweek int) (time.Time, error) {
+	event, err := r.event(ctx, fmt.Sprintf("gameweek %d", gameweek), func(event Event) bool { return event.ID == gameweek })
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return event.DeadlineTime, nil
+}
+
+func (r *Reference) event(ctx context.Context, name string, match func(Event) bool) (Event, error) {
+	data, err := r.get(ctx)
+	if err != nil {
+		return Event{}, err
+	}
+
+	for _, event := range data.bootstrap.Events {
+		if match(event) {
+			return event, nil
+		}
+	}
+
+	return Event{}, fmt.Errorf("%v event %w", name, ErrNotFound)
+}
@@ -0,0 +1,166 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fixtureReference replaces the reference data with testdata/bootstrap.json for the test
+func fixtureReference(t *testing.T) *Reference {
+	t.Helper()
+
+	var bootstrap BootstrapResponse
+	readTestdata(t, "bootstrap.json", &bootstrap)
+
+	saved := reference
+	reference = NewReference(func(context.Context) (BootstrapResponse, error) { return bootstrap, nil })
+	t.Cleanup(func() { reference = saved })
+
+	return reference
+}
+
+func TestReferenceLookups(t *testing.T) {
+	ref := fixtureReference(t)
+	ctx := context.Background()
+
+	if player, err := ref.Player(ctx, 10); err != nil || player.WebName != "Haaland" || player.Team != 8 {
+		t.Errorf("Player(10) = %+v, (%v), want Haaland of team 8", player, err)
+	}
+
+	if club, err := ref.Team(ctx, 8); err != nil || club.ShortName != "MCI" {
+		t.Errorf("Team(8) = %+v, (%v), want MCI", club, err)
+	}
+
+	if position, err := ref.Position(ctx, Forward); err != nil || position.SingularNameShort != "FWD" {
+		t.Errorf("Position(Forward) = %+v, (%v), want FWD", position, err)
+	}
+
+	if event, err := ref.CurrentEvent(ctx); err != nil || event.ID != 4 {
+		t.Errorf("CurrentEvent() = %+v, (%v), want gameweek 4", event, err)
+	}
+
+	if event, err := ref.NextEvent(ctx); err != nil || event.ID != 5 {
+		t.Errorf("NextEvent() = %+v, (%v), want gameweek 5", event, err)
+	}
+
+	want := time.Date(2024, 9, 21, 10, 0, 0, 0, time.UTC)
+	if deadline, err := ref.Deadline(ctx, 5); err != nil || !deadline.Equal(want) {
+		t.Errorf("Deadline(5) = %v, (%v), want %v", deadline, err, want)
+	}
+
+	if _, err := ref.Player(ctx, 999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Player(999) err = (%v), want: ErrNotFound", err)
+	}
+
+	if _, err := ref.Deadline(ctx, 39); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Deadline(39) err = (%v), want: ErrNotFound", err)
+	}
+}
+
+func TestReferenceRefresh(t *testing.T) {
+	var bootstrap BootstrapResponse
+	readTestdata(t, "bootstrap.json", &bootstrap)
+
+	var loads atomic.Int32
+
+	failing := false
+	ref := NewReference(func(context.Context) (BootstrapResponse, error) {
+		loads.Add(1)
+
+		if failing {
+			return BootstrapResponse{}, errors.New("game is updating")
+		}
+
+		return bootstrap, nil
+	})
+
+	// half an hour before the gameweek 5 deadline
+	now := time.Date(2024, 9, 21, 9, 30, 0, 0, time.UTC)
+	ref.now = func() time.Time { return now }
+
+	tests := []struct {
+		scenario string
+		advance  time.Duration
+		failing  bool
+		loads    int32
+	}{
+		{"first lookup loads", 0, false, 1},
+		{"reused before the deadline", 29 * time.Minute, false, 1},
+		{"refreshed after the deadline", 2 * time.Minute, false, 2},
+		{"reused for referenceTTL", referenceTTL - time.Minute, false, 2},
+		{"refreshed after referenceTTL", 2 * time.Minute, false, 3},
+		{"stale data served when refresh fails", referenceTTL, true, 4},
+		{"refresh retried after referenceRetryDelay", referenceRetryDelay, false, 5},
+	}
+
+	for _, test := range tests {
+		now = now.Add(test.advance)
+		failing = test.failing
+
+		if _, err := ref.Player(context.Background(), 1); err != nil {
+			t.Errorf("%s: Player(1) err = (%v), want: nil err", test.scenario, err)
+		}
+
+		if got := loads.Load(); got != test.loads {
+			t.Errorf("%s: loads = %d, want %d", test.scenario, got, test.loads)
+		}
+	}
+}
+
+func TestReferenceConcurrentRefresh(t *testing.T) {
+	var bootstrap BootstrapResponse
+	readTestdata(t, "bootstrap.json", &bootstrap)
+
+	var loads atomic.Int32
+
+	release := make(chan struct{})
+	ref := NewReference(func(context.Context) (BootstrapResponse, error) {
+		loads.Add(1)
+		<-release
+
+		return bootstrap, nil
+	})
+
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := ref.Team(context.Background(), 1); err != nil {
+				t.Errorf("Team(1) err = (%v), want: nil err", err)
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("loads = %d, want 1 shared by every caller", loads.Load())
+	}
+}
+
+func TestReferenceCancelledWait(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	ref := NewReference(func(context.Context) (BootstrapResponse, error) {
+		<-release
+		return BootstrapResponse{}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := ref.Bootstrap(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Bootstrap() err = (%v), want: context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,242 @@
+package fpl
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// snapshots directory used when environment variable "snapshots_dir" is not set
+const defaultSnapshotsDir = "snapshots"
+
+type Snapshot struct { // a manager's entry at the end of a gameweek, league ranks are set when a league is charted
+	Gameweek int `json:"gameweek"`
+	ManagerEntry
+}
+
+// A SnapshotStore keeps each manager's snapshots in a json file named by their id
+type SnapshotStore struct {
+	mu     sync.Mutex
+	latest map[string]int // the latest gameweek in each file, read on first use
+}
+
+var snapshots = &SnapshotStore{latest: map[string]int{}}
+
+// the directory in environment variable "snapshots_dir", or defaultSnapshotsDir
+func snapshotsDir() string {
+	if dir, ok := os.LookupEnv("snapshots_dir"); ok {
+		return dir
+	}
+
+	return defaultSnapshotsDir
+}
+
+func snapshotPath(manager string) string {
+	return filepath.Join(snapshotsDir(), manager+".json")
+}
+
+// Load returns the manager's snapshots in gameweek order, none if nothing has been stored
+func (s *SnapshotStore) Load(manager string) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(snapshotPath(manager))
+}
+
+func (s *SnapshotStore) load(path string) ([]Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read snapshots: %w", err)
+	}
+
+	var stored []Snapshot
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parse snapshots %v: %w", path, err)
+	}
+
+	return stored, nil
+}
+
+// Save adds the snapshots to the manager's file, replacing any of the same gameweek
+func (s *SnapshotStore) Save(manager string, add ...Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := snapshotPath(manager)
+
+	stored, err := s.load(path)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range add {
+		i := slices.IndexFunc(stored, func(stored Snapshot) bool { return stored.Gameweek == snapshot.Gameweek })
+		if i < 0 {
+			stored = append(stored, snapshot)
+		} else {
+			stored[i] = snapshot
+		}
+	}
+
+	slices.SortFunc(stored, func(a, b Snapshot) int { return cmp.Compare(a.Gameweek, b.Gameweek) })
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create snapshots directory: %w", err)
+	}
+
+	// written to a temporary file first so a failed write does not lose the stored snapshots
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write snapshots: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write snapshots: %w", err)
+	}
+
+	if len(stored) > 0 {
+		s.latest[path] = stored[len(stored)-1].Gameweek
+	}
+
+	return nil
+}
+
+// Latest returns the latest gameweek stored for the manager, 0 if there is none
+func (s *SnapshotStore) Latest(manager string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := snapshotPath(manager)
+	if latest, ok := s.latest[path]; ok {
+		return latest, nil
+	}
+
+	stored, err := s.load(path)
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	if len(stored) > 0 {
+		latest = stored[len(stored)-1].Gameweek
+	}
+
+	s.latest[path] = latest
+
+	return latest, nil
+}
+
+// recordSnapshots stores each manager's entry once the league's gameweek has finished and its bonus points
+// are confirmed. Failures are logged, the league is still served.
+func recordSnapshots(ctx context.Context, leagueResponse LeagueResponse) {
+	current, err := reference.CurrentEvent(ctx)
+	if err != nil || current.ID != leagueResponse.Gameweek || !current.Finished || !current.DataChecked {
+		return
+	}
+
+	for _, entry := range leagueResponse.League {
+		manager := strconv.Itoa(entry.ID)
+
+		latest, err := snapshots.Latest(manager)
+		if err == nil && latest >= current.ID {
+			continue
+		}
+
+		// league ranks depend on the league charted, chips are read from history
+		entry.LeagueRank, entry.PreviousLeagueRank = 0, 0
+		entry.Chips = nil
+
+		if err == nil {
+			err = snapshots.Save(manager, Snapshot{Gameweek: current.ID, ManagerEntry: entry})
+		}
+
+		if err != nil {
+			log.Printf("snapshot manager %v gameweek %d: %v", manager, current.ID, err)
+		}
+	}
+}
+
+// backfillSnapshots stores the finished gameweeks missing from the managers' snapshots from their history,
+// so a new deployment has the whole season. Gameweeks already stored are kept.
+func backfillSnapshots(ctx context.Context, managerList []string, finished []int) error {
+	stored := map[string][]Snapshot{}
+	missing := []string{}
+
+	for _, manager := range managerList {
+		managerSnapshots, err := snapshots.Load(manager)
+		if err != nil {
+			return err
+		}
+
+		stored[manager] = managerSnapshots
+
+		for _, gameweek := range finished {
+			if !slices.ContainsFunc(managerSnapshots, func(s Snapshot) bool { return s.Gameweek == gameweek }) {
+				missing = append(missing, manager)
+				break
+			}
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	historyResponse, err := getHistories(ctx, missing)
+	if err != nil {
+		return err
+	}
+
+	for _, history := range historyResponse.Managers {
+		manager := strconv.Itoa(history.ID)
+
+		var add []Snapshot
+
+		for _, gw := range history.Gameweeks {
+			if !slices.Contains(finished, gw.Gameweek) ||
+				slices.ContainsFunc(stored[manager], func(s Snapshot) bool { return s.Gameweek == gw.Gameweek }) {
+				continue
+			}
+
+			add = append(add, Snapshot{Gameweek: gw.Gameweek, ManagerEntry: ManagerEntry{
+				ID:              history.ID,
+				Name:            history.Name,
+				Team:            history.Team,
+				Points:          gw.TotalPoints,
+				Rank:            gw.OverallRank,
+				GwPoints:        gw.Points,
+				GwRank:          gw.GwRank,
+				GwTransfersCost: gw.TransfersCost,
+				Link:            fmt.Sprintf("https://fantasy.premierleague.com/entry/%v/event/%d", history.ID, gw.Gameweek),
+			}})
+		}
+
+		if len(add) == 0 {
+			continue
+		}
+
+		if err := snapshots.Save(manager, add...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package fpl
+
+import (
+	"context"
+	"testing"
+)
+
+// useSnapshotsDir stores snapshots in a directory removed after the test
+func useSnapshotsDir(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("snapshots_dir", t.TempDir())
+}
+
+func TestSnapshotStoreSave(t *testing.T) {
+	useSnapshotsDir(t)
+
+	if stored, err := snapshots.Load("1"); err != nil || stored != nil {
+		t.Fatalf("Load() with no file = %v, (%v), want: none", stored, err)
+	}
+
+	if err := snapshots.Save("1", Snapshot{Gameweek: 2, ManagerEntry: ManagerEntry{ID: 1, Points: 110}}, Snapshot{Gameweek: 1, ManagerEntry: ManagerEntry{ID: 1, Points: 60}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a gameweek saved again is replaced
+	if err := snapshots.Save("1", Snapshot{Gameweek: 2, ManagerEntry: ManagerEntry{ID: 1, Points: 112}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := snapshots.Load("1")
+	if err != nil || len(stored) != 2 || stored[0].Gameweek != 1 || stored[1].Points != 112 {
+		t.Errorf("Load() = %+v, (%v), want: gameweeks 1 and 2 with 112 points", stored, err)
+	}
+
+	if latest, err := snapshots.Latest("1"); err != nil || latest != 2 {
+		t.Errorf("Latest() = %d, (%v), want: 2", latest, err)
+	}
+}
+
+// finishedReference serves the fixture bootstrap-static with gameweek 4 finished and its data checked
+func finishedReference(t *testing.T) {
+	t.Helper()
+
+	var bootstrap BootstrapResponse
+	readTestdata(t, "bootstrap.json", &bootstrap)
+
+	bootstrap.Events[3].Finished = true
+	bootstrap.Events[3].DataChecked = true
+
+	saved := reference
+	reference = NewReference(func(context.Context) (BootstrapResponse, error) { return bootstrap, nil })
+	t.Cleanup(func() { reference = saved })
+}
+
+func TestRecordSnapshots(t *testing.T) {
+	useSnapshotsDir(t)
+
+	ctx := context.Background()
+
+	// gameweek 4 is still live
+	fixtureReference(t)
+	recordSnapshots(ctx, LeagueResponse{Gameweek: 4, League: []ManagerEntry{{ID: 1, Points: 200}}})
+
+	if stored, _ := snapshots.Load("1"); len(stored) != 0 {
+		t.Errorf("recordSnapshots() while live stored %+v, want: none", stored)
+	}
+
+	finishedReference(t)
+	recordSnapshots(ctx, LeagueResponse{Gameweek: 4, League: []ManagerEntry{{ID: 1, Points: 200, Rank: 5000, LeagueRank: 2, PreviousLeagueRank: 3}}})
+
+	// the first snapshot of a gameweek is kept
+	recordSnapshots(ctx, LeagueResponse{Gameweek: 4, League: []ManagerEntry{{ID: 1, Points: 999}}})
+
+	stored, err := snapshots.Load("1")
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("Load() = %+v, (%v), want: gameweek 4", stored, err)
+	}
+
+	if snapshot := stored[0]; snapshot.Gameweek != 4 || snapshot.Points != 200 || snapshot.Rank != 5000 || snapshot.LeagueRank != 0 || snapshot.PreviousLeagueRank != 0 {
+		t.Errorf("snapshot = %+v, want: gameweek 4 with 200 points, overall rank 5000 and no league ranks", snapshot)
+	}
+}
+
+func TestBackfillSnapshots(t *testing.T) {
+	useSnapshotsDir(t)
+	mockHistoryServer(t)
+
+	// a recorded gameweek is not replaced from history
+	if err := snapshots.Save("1", Snapshot{Gameweek: 2, ManagerEntry: ManagerEntry{ID: 1, Points: 111, Rank: 42}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// manager 2 has no history
+	if err := backfillSnapshots(context.Background(), []string{"1", "2"}, []int{1, 2, 3}); err != nil {
+		t.Fatalf("backfillSnapshots() err = (%v), want: nil err", err)
+	}
+
+	stored, err := snapshots.Load("1")
+	if err != nil || len(stored) != 2 {
+		t.Fatalf("Load() = %+v, (%v), want: gameweeks 1 and 2", stored, err)
+	}
+
+	if gw1 := stored[0]; gw1.Gameweek != 1 || gw1.Points != 64 || gw1.GwPoints != 64 || gw1.Rank != 2134567 || gw1.Team != "team1" {
+		t.Errorf("backfilled gameweek 1 = %+v, want: 64 points ranked 2134567 for team1", gw1)
+	}
+
+	if gw2 := stored[1]; gw2.Rank != 42 {
+		t.Errorf("recorded gameweek 2 = %+v, want: rank 42 kept", gw2)
+	}
+
+	if stored, _ := snapshots.Load("2"); len(stored) != 0 {
+		t.Errorf("manager 2 snapshots = %+v, want: none", stored)
+	}
+}
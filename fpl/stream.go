@@ -0,0 +1,233 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxStreamSubscribers = 100 // connected clients across every league
+
+// stream timings, shortened in tests
+var (
+	streamPollInterval = 30 * time.Second // how often a league is fetched while clients are connected
+	streamHeartbeat    = 15 * time.Second // how often a heartbeat is sent to keep connections open
+	streamRetry        = 5 * time.Second  // how long clients wait to reconnect
+)
+
+// ErrTooManySubscribers is returned when maxStreamSubscribers clients are connected
+var ErrTooManySubscribers = errors.New("too many stream subscribers")
+
+// a league update sent to subscribers, the id is the update time in unix milliseconds so it is unique
+// when a poller is restarted
+type streamEvent struct {
+	id   string
+	data []byte
+}
+
+// a league polled for its subscribers
+type leaguePoller struct {
+	managerList []string
+	leagueName  string
+	cancel      context.CancelFunc
+	last        streamEvent
+	lastID      int64
+	subscribers map[chan streamEvent]bool
+}
+
+// streamBroker shares one poller per league between the clients streaming it
+type streamBroker struct {
+	mu          sync.Mutex
+	subscribers int
+	pollers     map[string]*leaguePoller
+	polling     sync.WaitGroup // running pollers
+}
+
+var leagueStreams = streamBroker{pollers: map[string]*leaguePoller{}}
+
+// Stream sends the league scored live, as from getLive, as server-sent events, a "league" event whenever
+// a manager's live points change and a "heartbeat" event in between. A client reconnecting with the Last-Event-ID of the latest update
+// is not sent it again.
+func Stream(w http.ResponseWriter, r *http.Request) {
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	events, last, unsubscribe, err := leagueStreams.subscribe(managerList, leagueName)
+	if errors.Is(err, ErrTooManySubscribers) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(streamRetry.Seconds())))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+		return
+	}
+	defer unsubscribe()
+
+	// the stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("stream write deadline: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	if last.id != "" && last.id != r.Header.Get("Last-Event-ID") {
+		writeStreamEvent(w, last)
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		if err := rc.Flush(); err != nil {
+			log.Printf("stream flush: %v", err)
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			writeStreamEvent(w, event)
+		case now := <-heartbeat.C:
+			fmt.Fprintf(w, "event: heartbeat\ndata: %q\n\n", now.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, event streamEvent) {
+	fmt.Fprintf(w, "id: %s\nevent: league\ndata: %s\n\n", event.id, event.data)
+}
+
+// subscribe adds a client to the league's poller, starting it for the first client. The latest update
+// is returned with a channel of the updates after it.
+func (b *streamBroker) subscribe(managerList []string, leagueName string) (<-chan streamEvent, streamEvent, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers >= maxStreamSubscribers {
+		return nil, streamEvent{}, nil, ErrTooManySubscribers
+	}
+
+	key := leagueName + "|" + strings.Join(managerList, ",")
+
+	poller, ok := b.pollers[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		poller = &leaguePoller{managerList: managerList, leagueName: leagueName, cancel: cancel, subscribers: map[chan streamEvent]bool{}}
+		b.pollers[key] = poller
+
+		b.polling.Add(1)
+
+		go func() {
+			defer b.polling.Done()
+			b.poll(ctx, poller)
+		}()
+	}
+
+	// only the latest update is kept for a slow client
+	events := make(chan streamEvent, 1)
+	poller.subscribers[events] = true
+	b.subscribers++
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(poller.subscribers, events)
+		b.subscribers--
+
+		if len(poller.subscribers) == 0 {
+			poller.cancel()
+			delete(b.pollers, key)
+		}
+	}
+
+	return events, poller.last, unsubscribe, nil
+}
+
+// poll scores the league live every streamPollInterval until ctx is done, publishing it when points change
+func (b *streamBroker) poll(ctx context.Context, poller *leaguePoller) {
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	var previous []LiveEntry
+
+	for {
+		leagueResponse, err := getLive(ctx, poller.managerList)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			log.Printf("stream %v: %v", poller.managerList, err)
+		case previous == nil || pointsChanged(previous, leagueResponse.League):
+			previous = leagueResponse.League
+			leagueResponse.LeagueName = poller.leagueName
+			b.publish(poller, leagueResponse)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *streamBroker) publish(poller *leaguePoller, leagueResponse LiveLeagueResponse) {
+	data, err := json.Marshal(leagueResponse)
+	if err != nil {
+		log.Printf("stream marshal: %v", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// ids increase even for updates in the same millisecond
+	poller.lastID = max(time.Now().UnixMilli(), poller.lastID+1)
+	poller.last = streamEvent{id: strconv.FormatInt(poller.lastID, 10), data: data}
+
+	for events := range poller.subscribers {
+		// replace an update the client has not read yet
+		select {
+		case <-events:
+		default:
+		}
+
+		events <- poller.last
+	}
+}
+
+// pointsChanged reports whether any manager's live or projected points differ, or managers were added or dropped
+func pointsChanged(previous, league []LiveEntry) bool {
+	if len(previous) != len(league) {
+		return true
+	}
+
+	points := make(map[int][3]int, len(previous))
+	for _, entry := range previous {
+		points[entry.ID] = [3]int{entry.Points, entry.GwPoints, entry.ProjectedPoints}
+	}
+
+	for _, entry := range league {
+		if p, ok := points[entry.ID]; !ok || p != [3]int{entry.Points, entry.GwPoints, entry.ProjectedPoints} {
+			return true
+		}
+	}
+
+	return false
+}
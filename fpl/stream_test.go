@@ -0,0 +1,263 @@
+package fpl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type sseEvent struct {
+	id, event, data string
+}
+
+// readEvent returns the next event from the stream, skipping the retry interval
+func readEvent(t *testing.T, scanner *bufio.Scanner) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+
+	for scanner.Scan() {
+		field, value, _ := strings.Cut(scanner.Text(), ": ")
+
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.event = value
+		case "data":
+			event.data = value
+		case "":
+			if event.event != "" {
+				return event
+			}
+		}
+	}
+
+	t.Fatalf("stream ended: %v", scanner.Err())
+
+	return event
+}
+
+// readLeague returns the next league event, skipping heartbeats
+func readLeague(t *testing.T, scanner *bufio.Scanner) (sseEvent, LiveLeagueResponse) {
+	t.Helper()
+
+	for {
+		event := readEvent(t, scanner)
+		if event.event != "league" {
+			continue
+		}
+
+		var leagueResponse LiveLeagueResponse
+		if err := json.Unmarshal([]byte(event.data), &leagueResponse); err != nil {
+			t.Fatalf("league event data %q: %v", event.data, err)
+		}
+
+		return event, leagueResponse
+	}
+}
+
+// setStreamServers starts an FPL API where both managers have 50 points before gameweek 4, scored live with
+// captain Haaland, element 10, on the points returned for manager 1 and the vice-captain for manager 2, with
+// entries never cached, and a server streaming the managers
+func setStreamServers(t *testing.T, points *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	savedPoll, savedHeartbeat, savedEntries := streamPollInterval, streamHeartbeat, entryResponses
+	savedPicks, savedLive := fplPicksURL, fplLiveURL
+	streamPollInterval, streamHeartbeat = 5*time.Millisecond, 20*time.Millisecond
+	entryResponses = NewCache(loadEntry, func(context.Context, time.Time) time.Duration { return 0 })
+
+	t.Cleanup(func() {
+		// the pollers stop once the stream server has closed every connection
+		leagueStreams.polling.Wait()
+
+		streamPollInterval, streamHeartbeat, entryResponses = savedPoll, savedHeartbeat, savedEntries
+		fplPicksURL, fplLiveURL = savedPicks, savedLive
+	})
+
+	var picks PicksResponse
+	readTestdata(t, "picks.json", &picks)
+
+	var live LiveElementsResponse
+	readTestdata(t, "live.json", &live)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entry/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		fmt.Fprintf(w, `{"id":%v,"name":"Team %v","current_event":4,"summary_overall_points":50,"summary_event_points":0}`, id, id)
+	})
+	mux.HandleFunc("GET /entry/{id}/event/4/picks/", func(w http.ResponseWriter, r *http.Request) {
+		managerPicks := picks
+		managerPicks.Picks = slices.Clone(picks.Picks)
+
+		if r.PathValue("id") == "2" {
+			for i := range managerPicks.Picks {
+				pick := &managerPicks.Picks[i]
+				pick.IsCaptain, pick.IsViceCaptain = pick.IsViceCaptain, pick.IsCaptain
+			}
+		}
+
+		json.NewEncoder(w).Encode(managerPicks)
+	})
+	mux.HandleFunc("GET /event/4/live/", func(w http.ResponseWriter, _ *http.Request) {
+		elements := slices.Clone(live.Elements)
+		for i := range elements {
+			if elements[i].ID == 10 {
+				elements[i].Stats.TotalPoints = int(points.Load())
+			}
+		}
+
+		json.NewEncoder(w).Encode(LiveElementsResponse{Elements: elements})
+	})
+
+	fplServer := httptest.NewServer(mux)
+	t.Cleanup(fplServer.Close)
+
+	fplURL = fplServer.URL + "/entry/%v/"
+	fplPicksURL = fplServer.URL + "/entry/%v/event/%d/picks/"
+	fplLiveURL = fplServer.URL + "/event/%d/live/"
+
+	mockFixtures(t)
+
+	t.Setenv("managers", "1,2")
+
+	streamServer := httptest.NewServer(http.HandlerFunc(Stream))
+	t.Cleanup(streamServer.Close)
+
+	return streamServer
+}
+
+func openStream(t *testing.T, ctx context.Context, url, lastEventID string) *bufio.Scanner {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("stream = %d %q, want 200 text/event-stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	return bufio.NewScanner(resp.Body)
+}
+
+func TestStream(t *testing.T) {
+	var points atomic.Int32
+
+	streamServer := setStreamServers(t, &points)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := openStream(t, ctx, streamServer.URL, "")
+
+	// manager 1 scores 31 before their hit with the captain on 0, manager 2 scores 39 with the vice-captain's 8 doubled
+	first, leagueResponse := readLeague(t, stream)
+	if first.id == "" || len(leagueResponse.League) != 2 || leagueResponse.League[1].ID != 1 || leagueResponse.League[1].Points != 50+31 {
+		t.Fatalf("first league event id %q = %+v, want manager 1 with %d points second", first.id, leagueResponse.League, 50+31)
+	}
+
+	// unchanged points are not sent again, only heartbeats
+	if event := readEvent(t, stream); event.event != "heartbeat" {
+		t.Errorf("event after the league = %+v, want heartbeat", event)
+	}
+
+	// the captain scores 20 during the matches, before the summary points change
+	points.Store(20)
+
+	second, leagueResponse := readLeague(t, stream)
+	if second.id <= first.id || leagueResponse.League[0].ID != 1 || leagueResponse.League[0].Points != 50+31+2*20 {
+		t.Errorf("league event id %q after %q = %+v, want manager 1 first with %d points", second.id, first.id, leagueResponse.League, 50+31+2*20)
+	}
+
+	// a client reconnecting with the latest id shares the poller and is not sent the update again
+	reconnected := openStream(t, ctx, streamServer.URL, second.id)
+	if event := readEvent(t, reconnected); event.event != "heartbeat" {
+		t.Errorf("reconnected with the latest id first event = %+v, want heartbeat", event)
+	}
+
+	// a client with an older id is sent the latest update
+	behind := openStream(t, ctx, streamServer.URL, first.id)
+	if event, _ := readLeague(t, behind); event.id != second.id {
+		t.Errorf("reconnected with an old id first league event %q, want %q", event.id, second.id)
+	}
+
+	leagueStreams.mu.Lock()
+	pollers, subscribers := len(leagueStreams.pollers), leagueStreams.subscribers
+	leagueStreams.mu.Unlock()
+
+	if pollers != 1 || subscribers != 3 {
+		t.Errorf("pollers = %d subscribers = %d, want: 1 poller for 3 subscribers", pollers, subscribers)
+	}
+}
+
+func TestStreamSubscriberCap(t *testing.T) {
+	t.Setenv("managers", "1,2")
+
+	leagueStreams.mu.Lock()
+	leagueStreams.subscribers += maxStreamSubscribers
+	leagueStreams.mu.Unlock()
+
+	t.Cleanup(func() {
+		leagueStreams.mu.Lock()
+		leagueStreams.subscribers -= maxStreamSubscribers
+		leagueStreams.mu.Unlock()
+	})
+
+	rec := httptest.NewRecorder()
+	Stream(rec, httptest.NewRequest(http.MethodGet, "/fpl/stream", http.NoBody))
+
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Stream() with too many subscribers = %d with Retry-After %q, want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestPointsChanged(t *testing.T) {
+	entry := func(id, points, gwPoints, projected int) LiveEntry {
+		return LiveEntry{ManagerEntry{ID: id, Points: points, GwPoints: gwPoints}, LiveScore{ProjectedPoints: projected}}
+	}
+
+	league := []LiveEntry{entry(1, 50, 10, 12), entry(2, 40, 5, 5)}
+
+	tests := []struct {
+		name  string
+		after []LiveEntry
+		want  bool
+	}{
+		{"unchanged, reordered", []LiveEntry{league[1], league[0]}, false},
+		{"gameweek points", []LiveEntry{league[0], entry(2, 40, 7, 5)}, true},
+		{"total points", []LiveEntry{entry(1, 51, 10, 12), league[1]}, true},
+		{"projected points", []LiveEntry{entry(1, 50, 10, 14), league[1]}, true},
+		{"manager dropped", league[:1], true},
+		{"manager replaced", []LiveEntry{league[0], entry(3, 40, 5, 5)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pointsChanged(league, tt.after); got != tt.want {
+				t.Errorf("pointsChanged() = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package fpl
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// functions for the table template
+var tableFuncs = template.FuncMap{
+	"sub":      func(a, b int) int { return a - b },
+	"chipName": chipName,
+}
+
+// Table displays the league from getData as an html table
+func Table(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	leagueResponse, err := getDataOrLast(r.Context(), managerList)
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	leagueResponse.LeagueName = leagueName
+	setCacheAge(w, leagueResponse.fetched)
+
+	tableTemplate := template.Must(template.New("TableTemplate.html").Funcs(tableFuncs).ParseFiles("fpl/TableTemplate.html"))
+	if err := tableTemplate.Execute(w, leagueResponse); err != nil {
+		log.Printf("error executing tableTemplate: %v", err)
+	}
+}
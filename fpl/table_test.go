@@ -0,0 +1,53 @@
+package fpl
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTableTemplate(t *testing.T) {
+	tableTemplate := template.Must(template.New("TableTemplate.html").Funcs(tableFuncs).ParseFiles("TableTemplate.html"))
+
+	leagueResponse := LeagueResponse{
+		Gameweek: Gameweek,
+		League: []ManagerEntry{
+			{ID: 2, Name: "first2 last2", Team: "team2", LeagueRank: 1, PreviousLeagueRank: 3, Link: "https://fantasy.premierleague.com/entry/2/event/99"},
+			{ID: 1, Name: "first1 last1", Team: "team<1>", LeagueRank: 2, PreviousLeagueRank: 1},
+		},
+		Errors: []ManagerError{{ID: "3", Error: "not OK"}, {ID: "4", Status: http.StatusNotFound, Error: "not found"}},
+	}
+
+	var page strings.Builder
+	if err := tableTemplate.Execute(&page, leagueResponse); err != nil {
+		t.Fatalf("Execute() err = (%v), want: nil err", err)
+	}
+
+	for _, want := range []string{
+		`<a href="https://fantasy.premierleague.com/entry/2/event/99">team2</a>`,
+		`<span class="up">&#9650;2</span>`,
+		`<span class="down">&#9660;1</span>`,
+		"team&lt;1&gt;",
+		"<li>3: not OK</li>",
+		"<li>4: not found (status 404)</li>",
+	} {
+		if !strings.Contains(page.String(), want) {
+			t.Errorf("table page does not contain %q\n%s", want, page.String())
+		}
+	}
+
+	if strings.Contains(page.String(), "being updated") {
+		t.Errorf("table page shows the updating banner, want none while the game is not updating\n%s", page.String())
+	}
+
+	page.Reset()
+	leagueResponse.Updating = true
+	if err := tableTemplate.Execute(&page, leagueResponse); err != nil {
+		t.Fatalf("Execute() updating err = (%v), want: nil err", err)
+	}
+
+	if !strings.Contains(page.String(), "The game is being updated") {
+		t.Errorf("table page does not show the updating banner\n%s", page.String())
+	}
+}
@@ -0,0 +1,299 @@
+package fpl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// gameweeks a transfer is judged over when query parameter "n" is not set
+const defaultTransferWindow = 5
+
+var (
+	fplTransfersURL      = "https://fantasy.premierleague.com/api/entry/%v/transfers/"
+	fplElementSummaryURL = "https://fantasy.premierleague.com/api/element-summary/%v/"
+)
+
+type TransferResponse struct { // fields retrieved from FPL entry transfers API
+	ElementIn      int       `json:"element_in"`
+	ElementInCost  int       `json:"element_in_cost"` // tenths of a million
+	ElementOut     int       `json:"element_out"`
+	ElementOutCost int       `json:"element_out_cost"` // tenths of a million
+	Event          int       `json:"event"`
+	Time           time.Time `json:"time"`
+}
+type ElementSummaryResponse struct { // fields retrieved from FPL element summary API
+	History []struct {
+		Round       int `json:"round"`
+		TotalPoints int `json:"total_points"`
+	} `json:"history"`
+}
+type Transfer struct { // a transfer and the points it gained over the gameweeks after it
+	Gameweek  int     `json:"gameweek"`
+	Time      string  `json:"time"`
+	In        Player  `json:"in"`
+	Out       Player  `json:"out"`
+	InCost    float64 `json:"in_cost"`  // £m
+	OutCost   float64 `json:"out_cost"` // £m
+	InPoints  int     `json:"in_points"`
+	OutPoints int     `json:"out_points"`
+	NetPoints int     `json:"net_points"`
+	Gameweeks int     `json:"gameweeks"` // played so far of the window
+	FreeHit   bool    `json:"free_hit"`  // made for a free hit, so judged on that gameweek only
+}
+type ManagerTransfers struct { // a manager's transfers this season and what they gained after hits
+	ID                 int        `json:"id"`
+	Name               string     `json:"name"`
+	Team               string     `json:"team"`
+	TransfersCost      int        `json:"transfers_cost"` // points spent on hits
+	NetPoints          int        `json:"net_points"`
+	NetPointsAfterHits int        `json:"net_points_after_hits"`
+	Transfers          []Transfer `json:"transfers"`
+}
+type LeagueTransfer struct { // a transfer and the manager who made it
+	AwardWinner
+	Transfer
+}
+type ManagerTransfersResult struct { // result wrapper for a manager's history and transfers, Error
+	ManagerID string
+	History   ManagerHistory
+	Transfers []TransferResponse
+	Error     error
+}
+
+func (result ManagerTransfersResult) managerError() (string, error) {
+	return result.ManagerID, result.Error
+}
+
+type TransfersResponse struct { // response with every manager's transfers and the best and worst in the league
+	LeagueName string             `json:"league_name,omitempty"`
+	Window     int                `json:"window"` // gameweeks each transfer is judged over
+	Timestamp  string             `json:"timestamp"`
+	Status     string             `json:"status"`
+	Managers   []ManagerTransfers `json:"managers"`
+	Best       *LeagueTransfer    `json:"best"`
+	Worst      *LeagueTransfer    `json:"worst"`
+	Errors     []ManagerError     `json:"errors"`
+}
+
+// Transfers writes every transfer the managers have made this season as json, judged on the points of the
+// players in and out over the gameweeks in query parameter "n", or defaultTransferWindow
+func Transfers(w http.ResponseWriter, r *http.Request) {
+	r, cancel := withResponseTimeout(r)
+	defer cancel()
+
+	window := defaultTransferWindow
+
+	if n := r.URL.Query().Get("n"); n != "" {
+		var err error
+		if window, err = strconv.Atoi(n); err != nil || window < 1 {
+			http.Error(w, "query parameter -n- must be a number of gameweeks", http.StatusBadRequest)
+			return
+		}
+	}
+
+	managerList, leagueName, ok := requestManagers(w, r)
+	if !ok {
+		return
+	}
+
+	transfersResponse, err := getTransfers(r.Context(), managerList, window)
+	if err != nil {
+		writeDataError(w, err)
+
+		return
+	}
+
+	transfersResponse.LeagueName = leagueName
+
+	writeJSON(w, transfersResponse)
+}
+
+// getTransfers gets each manager's history and transfers, then the gameweek points of every player
+// transferred in or out. Managers that could not be retrieved, or with a player whose points could not be,
+// are listed in the errors.
+func getTransfers(ctx context.Context, managerList []string, window int) (TransfersResponse, error) {
+	results, err := fetchAll(ctx, managerList, getManagerTransfers)
+	if err != nil {
+		return TransfersResponse{}, err
+	}
+
+	elements := map[int]bool{}
+
+	for _, result := range results {
+		for _, transfer := range result.Transfers {
+			elements[transfer.ElementIn] = true
+			elements[transfer.ElementOut] = true
+		}
+	}
+
+	points, err := getElementPoints(ctx, elements)
+	if err != nil {
+		return TransfersResponse{}, err
+	}
+
+	// a manager can not be judged without the points of every player they transferred
+	for i := range results {
+		for _, transfer := range results[i].Transfers {
+			results[i].Error = cmp.Or(results[i].Error, points[transfer.ElementIn].err, points[transfer.ElementOut].err)
+		}
+	}
+
+	retrieved, managerErrors, err := collectResults(results)
+	if err != nil {
+		return TransfersResponse{}, err
+	}
+
+	players, err := reference.Players(ctx)
+	if err != nil {
+		return TransfersResponse{}, err
+	}
+
+	transfersResponse := TransfersResponse{
+		Window:    window,
+		Timestamp: time.Now().Format(timestampLayout),
+		Status:    responseStatus(managerErrors),
+		Managers:  []ManagerTransfers{},
+		Errors:    managerErrors,
+	}
+
+	for _, i := range retrieved {
+		result := results[i]
+		manager := judgeTransfers(result.History, result.Transfers, points, players, window)
+		transfersResponse.Managers = append(transfersResponse.Managers, manager)
+
+		for _, transfer := range manager.Transfers {
+			leagueTransfer := &LeagueTransfer{AwardWinner{ID: manager.ID, Name: manager.Name, Team: manager.Team}, transfer}
+
+			if transfersResponse.Best == nil || transfer.NetPoints > transfersResponse.Best.NetPoints {
+				transfersResponse.Best = leagueTransfer
+			}
+
+			if transfersResponse.Worst == nil || transfer.NetPoints < transfersResponse.Worst.NetPoints {
+				transfersResponse.Worst = leagueTransfer
+			}
+		}
+	}
+
+	return transfersResponse, nil
+}
+
+func getManagerTransfers(ctx context.Context, entry string) ManagerTransfersResult {
+	history := getManagerHistory(ctx, entry)
+	if history.Error != nil {
+		return ManagerTransfersResult{ManagerID: entry, Error: history.Error}
+	}
+
+	var transfers []TransferResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplTransfersURL, entry), &transfers); err != nil {
+		return ManagerTransfersResult{ManagerID: entry, Error: fmt.Errorf("get transfers for manager ID %v %w", entry, err)}
+	}
+
+	return ManagerTransfersResult{ManagerID: entry, History: history.History, Transfers: transfers}
+}
+
+// an element's points in each gameweek, summed over double gameweeks
+type elementPoints struct {
+	rounds map[int]int
+	err    error
+}
+
+// the gameweek points of each element, reused for as long as manager entries are
+var elementRounds = NewCache(getElementRounds, entryTTL)
+
+// getElementRounds gets the element's points in each gameweek from its element summary
+func getElementRounds(ctx context.Context, element int) (map[int]int, error) {
+	var summary ElementSummaryResponse
+	if err := getJSON(ctx, fmt.Sprintf(fplElementSummaryURL, element), &summary); err != nil {
+		return nil, fmt.Errorf("get summary for element %v %w", element, err)
+	}
+
+	rounds := map[int]int{}
+	for _, fixture := range summary.History {
+		rounds[fixture.Round] += fixture.TotalPoints
+	}
+
+	return rounds, nil
+}
+
+// getElementPoints gets the gameweek points of each element, with an error for those that could not be
+// retrieved. It only fails when the client has gone.
+func getElementPoints(ctx context.Context, elements map[int]bool) (map[int]elementPoints, error) {
+	ids := make([]string, 0, len(elements))
+	for element := range elements {
+		ids = append(ids, strconv.Itoa(element))
+	}
+
+	results, err := fetchAll(ctx, ids, func(ctx context.Context, id string) elementPoints {
+		element, _ := strconv.Atoi(id)
+		rounds, _, err := elementRounds.get(ctx, element)
+
+		return elementPoints{rounds: rounds, err: err}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	points := make(map[int]elementPoints, len(results))
+
+	for i, result := range results {
+		element, _ := strconv.Atoi(ids[i])
+		points[element] = result
+	}
+
+	return points, nil
+}
+
+// judgeTransfers scores each transfer on the points of the player in less the player out over the window
+// from the transfer's gameweek, up to the latest gameweek the manager has played. A free hit squad is only
+// kept for its gameweek so its transfers are judged on that gameweek. Transfers are in the order they were made.
+func judgeTransfers(history ManagerHistory, transfers []TransferResponse, points map[int]elementPoints, players map[int]Player, window int) ManagerTransfers {
+	manager := ManagerTransfers{ID: history.ID, Name: history.Name, Team: history.Team, Transfers: []Transfer{}}
+
+	played := 0
+	freeHits := map[int]bool{}
+
+	for _, gw := range history.Gameweeks {
+		manager.TransfersCost += gw.TransfersCost
+		played = max(played, gw.Gameweek)
+		freeHits[gw.Gameweek] = gw.Chip == ChipFreeHit
+	}
+
+	transfers = slices.Clone(transfers)
+	slices.SortStableFunc(transfers, func(a, b TransferResponse) int { return a.Time.Compare(b.Time) })
+
+	for _, t := range transfers {
+		transfer := Transfer{
+			Gameweek: t.Event,
+			Time:     t.Time.Format(time.RFC3339),
+			In:       playerName(players, t.ElementIn),
+			Out:      playerName(players, t.ElementOut),
+			InCost:   float64(t.ElementInCost) / 10,
+			OutCost:  float64(t.ElementOutCost) / 10,
+			FreeHit:  freeHits[t.Event],
+		}
+
+		gameweeks := window
+		if transfer.FreeHit {
+			gameweeks = 1
+		}
+
+		for gw := t.Event; gw < t.Event+gameweeks && gw <= played; gw++ {
+			transfer.InPoints += points[t.ElementIn].rounds[gw]
+			transfer.OutPoints += points[t.ElementOut].rounds[gw]
+			transfer.Gameweeks++
+		}
+
+		transfer.NetPoints = transfer.InPoints - transfer.OutPoints
+		manager.NetPoints += transfer.NetPoints
+		manager.Transfers = append(manager.Transfers, transfer)
+	}
+
+	manager.NetPointsAfterHits = manager.NetPoints - manager.TransfersCost
+
+	return manager
+}
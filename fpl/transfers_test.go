@@ -0,0 +1,238 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// mockTransfersServer serves manager 1's transfers and the element summaries of the players in them,
+// counting the summary requests, with the history of mockHistoryServer
+func mockTransfersServer(t *testing.T) *atomic.Int32 {
+	t.Helper()
+
+	mockHistoryServer(t)
+
+	transfers, err := os.ReadFile("testdata/transfers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Haaland has a double gameweek 2
+	rounds := map[string][][2]int{
+		"6":  {{1, 5}, {2, 2}, {3, 9}},
+		"10": {{1, 2}, {2, 13}, {2, 2}, {3, 8}},
+		"11": {{1, 6}, {2, 3}, {3, 1}},
+		"16": {{1, 9}, {2, 4}, {3, 14}},
+	}
+
+	var summaryRequests atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entry/{id}/transfers/", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set(ContentType, ApplicationJSON)
+		w.Write(transfers)
+	})
+	mux.HandleFunc("GET /element-summary/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		summaryRequests.Add(1)
+
+		summary := ElementSummaryResponse{}
+		for _, round := range rounds[r.PathValue("id")] {
+			summary.History = append(summary.History, struct {
+				Round       int `json:"round"`
+				TotalPoints int `json:"total_points"`
+			}{round[0], round[1]})
+		}
+
+		w.Header().Set(ContentType, ApplicationJSON)
+
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			panic(err)
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	fplTransfersURL = ts.URL + "/entry/%v/transfers/"
+	fplElementSummaryURL = ts.URL + "/element-summary/%v/"
+	resetCaches(t)
+
+	return &summaryRequests
+}
+
+func TestGetTransfers(t *testing.T) {
+	summaryRequests := mockTransfersServer(t)
+	fixtureReference(t)
+
+	// manager 1 has played gameweeks 1 and 2, manager 2 has no history
+	transfersResponse, err := getTransfers(context.Background(), []string{"1", "2"}, 5)
+	if err != nil {
+		t.Fatalf("getTransfers() err = (%v), want: nil err", err)
+	}
+
+	if transfersResponse.Status != StatusDegraded || len(transfersResponse.Errors) != 1 || len(transfersResponse.Managers) != 1 {
+		t.Fatalf("getTransfers() = %+v, want: manager 1 with manager 2 in the errors", transfersResponse)
+	}
+
+	manager := transfersResponse.Managers[0]
+	if manager.Team != "team1" || manager.TransfersCost != 4 || manager.NetPoints != 12 || manager.NetPointsAfterHits != 8 || len(manager.Transfers) != 2 {
+		t.Fatalf("manager = %+v, want: team1 net 12 points, 8 after a 4 point hit, from 2 transfers", manager)
+	}
+
+	// only gameweek 2 has been played since the first transfer, the second is yet to play
+	first := manager.Transfers[0]
+	want := Transfer{
+		Gameweek:  2,
+		Time:      "2024-08-23T09:12:45Z",
+		In:        Player{Element: 10, Name: "Haaland", Club: "MCI"},
+		Out:       Player{Element: 11, Name: "Isak", Club: "NEW"},
+		InCost:    15,
+		OutCost:   8.5,
+		InPoints:  15,
+		OutPoints: 3,
+		NetPoints: 12,
+		Gameweeks: 1,
+	}
+
+	if first != want {
+		t.Errorf("first transfer = %+v, want: %+v", first, want)
+	}
+
+	if second := manager.Transfers[1]; second.Gameweek != 3 || second.Gameweeks != 0 || second.NetPoints != 0 {
+		t.Errorf("second transfer = %+v, want: gameweek 3 with no gameweeks played", second)
+	}
+
+	if best, worst := transfersResponse.Best, transfersResponse.Worst; best == nil || worst == nil || best.In.Element != 10 || worst.In.Element != 6 || best.Team != "team1" {
+		t.Errorf("best = %+v worst = %+v, want: Haaland in then Palmer in, by team1", best, worst)
+	}
+
+	// the 4 players' summaries are reused
+	if _, err := getTransfers(context.Background(), []string{"1"}, 5); err != nil || summaryRequests.Load() != 4 {
+		t.Errorf("getTransfers() again err = (%v) after %d summary requests, want: nil err after 4", err, summaryRequests.Load())
+	}
+}
+
+func TestGetTransfersElementError(t *testing.T) {
+	mockTransfersServer(t)
+	fixtureReference(t)
+
+	history, err := os.ReadFile("testdata/history.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfers, err := os.ReadFile("testdata/transfers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summaries := strings.TrimSuffix(fplElementSummaryURL, "%v/")
+
+	// manager 1 transferred out element 16, whose summary is not found, manager 2 only Isak for Haaland
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /entry/{id}/history/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(history)
+	})
+	mux.HandleFunc("GET /entry/{id}/transfers/", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "1" {
+			w.Write(transfers)
+			return
+		}
+
+		fmt.Fprint(w, `[{"element_in": 10, "element_in_cost": 150, "element_out": 11, "element_out_cost": 85, "event": 2, "time": "2024-08-23T09:12:45Z"}]`)
+	})
+	mux.HandleFunc("GET /element-summary/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "16" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, summaries+r.PathValue("id")+"/", http.StatusFound)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	fplHistoryURL = ts.URL + "/entry/%v/history/"
+	fplTransfersURL = ts.URL + "/entry/%v/transfers/"
+	fplElementSummaryURL = ts.URL + "/element-summary/%v/"
+	resetCaches(t)
+
+	transfersResponse, err := getTransfers(context.Background(), []string{"1", "2"}, 5)
+	if err != nil {
+		t.Fatalf("getTransfers() err = (%v), want: nil err", err)
+	}
+
+	if transfersResponse.Status != StatusDegraded || len(transfersResponse.Errors) != 1 || transfersResponse.Errors[0].ID != "1" ||
+		transfersResponse.Errors[0].Status != http.StatusNotFound {
+		t.Errorf("getTransfers() status = %v errors = %+v, want: manager 1 not found", transfersResponse.Status, transfersResponse.Errors)
+	}
+
+	if len(transfersResponse.Managers) != 1 || transfersResponse.Managers[0].Team != "team2" || transfersResponse.Managers[0].NetPoints != 12 {
+		t.Errorf("getTransfers() managers = %+v, want: team2 with 12 net points", transfersResponse.Managers)
+	}
+}
+
+func TestJudgeTransfersFreeHit(t *testing.T) {
+	history := ManagerHistory{ID: 1, Gameweeks: []GameweekHistory{{Gameweek: 1}, {Gameweek: 2, Chip: ChipFreeHit}, {Gameweek: 3}}}
+	points := map[int]elementPoints{
+		1: {rounds: map[int]int{2: 10, 3: 1}},
+		2: {rounds: map[int]int{2: 2, 3: 8}},
+	}
+	transfers := []TransferResponse{{ElementIn: 1, ElementOut: 2, Event: 2}, {ElementIn: 2, ElementOut: 1, Event: 3}}
+
+	// the free hit squad is judged on gameweek 2 alone, the transfer after it over the window
+	manager := judgeTransfers(history, transfers, points, nil, 5)
+	if first := manager.Transfers[0]; !first.FreeHit || first.NetPoints != 8 || first.Gameweeks != 1 {
+		t.Errorf("free hit transfer = %+v, want: net 8 over 1 gameweek", first)
+	}
+
+	if second := manager.Transfers[1]; second.FreeHit || second.NetPoints != 7 || second.Gameweeks != 1 {
+		t.Errorf("transfer after the free hit = %+v, want: net 7 over the 1 gameweek played", second)
+	}
+}
+
+func TestJudgeTransfersWindow(t *testing.T) {
+	history := ManagerHistory{ID: 1}
+	for gw := 1; gw <= 6; gw++ {
+		history.Gameweeks = append(history.Gameweeks, GameweekHistory{Gameweek: gw})
+	}
+
+	points := map[int]elementPoints{
+		1: {rounds: map[int]int{2: 10, 3: 1, 4: 6, 5: 2}},
+		2: {rounds: map[int]int{2: 2, 3: 8, 4: 2, 5: 9}},
+	}
+	transfers := []TransferResponse{{ElementIn: 1, ElementOut: 2, Event: 2}}
+
+	tests := []struct {
+		window    int
+		wantNet   int
+		wantWeeks int
+	}{
+		{1, 8, 1},
+		{2, 1, 2},
+		{4, -2, 4},
+		{10, -2, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d gameweeks", tt.window), func(t *testing.T) {
+			transfer := judgeTransfers(history, transfers, points, nil, tt.window).Transfers[0]
+			if transfer.NetPoints != tt.wantNet || transfer.Gameweeks != tt.wantWeeks {
+				t.Errorf("transfer net %d over %d gameweeks, want: %d over %d", transfer.NetPoints, transfer.Gameweeks, tt.wantNet, tt.wantWeeks)
+			}
+		})
+	}
+}
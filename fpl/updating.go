@@ -0,0 +1,84 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// most manager lists with a last league kept, the one fetched longest ago is dropped for another
+const maxLastLeagues = 100
+
+// the last league served for each manager list, served again while the game is updating
+type lastLeagues struct {
+	mu        sync.Mutex
+	responses map[string]LeagueResponse
+}
+
+var lastLeague = lastLeagues{responses: map[string]LeagueResponse{}}
+
+func (c *lastLeagues) get(managerList []string) (LeagueResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	response, ok := c.responses[strings.Join(managerList, ",")]
+
+	return response, ok
+}
+
+func (c *lastLeagues) put(managerList []string, response LeagueResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := strings.Join(managerList, ",")
+
+	if _, ok := c.responses[key]; !ok && len(c.responses) >= maxLastLeagues {
+		var oldest string
+
+		for k, last := range c.responses {
+			if oldest == "" || last.fetched.Before(c.responses[oldest].fetched) {
+				oldest = k
+			}
+		}
+
+		delete(c.responses, oldest)
+	}
+
+	c.responses[key] = response
+}
+
+// getDataOrLast returns the league from getData with each manager's chips, or while the game is updating the last league served
+// for the managers with updating set
+func getDataOrLast(ctx context.Context, managerList []string) (LeagueResponse, error) {
+	leagueResponse, err := getData(ctx, managerList)
+	if err == nil {
+		addChips(ctx, leagueResponse)
+		lastLeague.put(managerList, leagueResponse)
+		recordSnapshots(ctx, leagueResponse)
+
+		return leagueResponse, nil
+	}
+
+	if errors.Is(err, ErrUpdating) {
+		if last, ok := lastLeague.get(managerList); ok {
+			last.Updating = true
+			return last, nil
+		}
+	}
+
+	return LeagueResponse{}, err
+}
+
+// write an error getting data from the FPL API, service unavailable while the game is updating
+func writeDataError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrUpdating) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	fmt.Fprintf(w, "%+v\n", err)
+}
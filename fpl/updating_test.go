@@ -0,0 +1,93 @@
+package fpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// an FPL API serving the maintenance page to every request
+func setUpdatingServer(t *testing.T) {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "<html><body><h1>The game is being updated.</h1></body></html>")
+	}))
+	t.Cleanup(ts.Close)
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+}
+
+func TestPointsUpdating(t *testing.T) {
+	t.Setenv("managers", "1,2")
+
+	lastLeague = lastLeagues{responses: map[string]LeagueResponse{}}
+
+	servePoints := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		Points(rec, httptest.NewRequest(http.MethodGet, "/fpl", http.NoBody))
+
+		return rec
+	}
+
+	// no league has been served yet
+	setUpdatingServer(t)
+
+	if rec := servePoints(); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Points() while updating with no cache = %d %q, want 503", rec.Code, rec.Body.String())
+	}
+
+	ts := setTestServer()
+	defer ts.Close()
+
+	fplURL = ts.URL + EntryPlaceholder
+	resetCaches(t)
+
+	var leagueResponse LeagueResponse
+	if err := json.Unmarshal(servePoints().Body.Bytes(), &leagueResponse); err != nil || leagueResponse.Updating {
+		t.Fatalf("Points() = (%+v, %v), want: league not updating", leagueResponse, err)
+	}
+
+	// the last league is served while the game is updating
+	setUpdatingServer(t)
+
+	rec := servePoints()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Points() while updating = %d %q, want 200", rec.Code, rec.Body.String())
+	}
+
+	var updatingResponse LeagueResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &updatingResponse); err != nil {
+		t.Fatalf("Points() body %q: %v", rec.Body.String(), err)
+	}
+
+	if !updatingResponse.Updating || len(updatingResponse.League) != 2 || updatingResponse.Timestamp != leagueResponse.Timestamp {
+		t.Errorf("Points() while updating = %+v, want: the last league with updating true", updatingResponse)
+	}
+}
+
+func TestLastLeaguesBounded(t *testing.T) {
+	leagues := lastLeagues{responses: map[string]LeagueResponse{}}
+	start := time.Now()
+
+	for i := range maxLastLeagues + 1 {
+		leagues.put([]string{strconv.Itoa(i)}, LeagueResponse{fetched: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	if len(leagues.responses) != maxLastLeagues {
+		t.Errorf("lastLeagues holds %d leagues, want: %d", len(leagues.responses), maxLastLeagues)
+	}
+
+	if _, ok := leagues.get([]string{"0"}); ok {
+		t.Error("lastLeagues kept the league fetched longest ago")
+	}
+
+	if _, ok := leagues.get([]string{strconv.Itoa(maxLastLeagues)}); !ok {
+		t.Error("lastLeagues dropped the league just served")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"math"
 	"net/http"
 	"time"
+
+	"github.com/mick4711/moh/web"
 )
 
 var templ = template.Must(template.New("webpage").Parse(`
@@ -60,12 +62,7 @@ const (
 func DogStats(w http.ResponseWriter, _ *http.Request) {
 	dob := time.Date(2022, 7, 28, 12, 0, 0, 0, time.Local)
 
-	loc, err := time.LoadLocation("Europe/Dublin")
-	if err != nil {
-		loc = time.UTC
-	}
-
-	age := getAge(dob, time.Now().In(loc))
+	age := getAge(dob, time.Now().In(web.Dublin()))
 
 	result := DogStat{
 		Name:        "Huxley",
@@ -8,8 +8,12 @@ import (
 	"time"
 
 	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/cors"
 	"github.com/mick4711/moh/fpl"
 	"github.com/mick4711/moh/huxley"
+	"github.com/mick4711/moh/matches"
+	"github.com/mick4711/moh/scorers"
+	"github.com/mick4711/moh/team"
 )
 
 const (
@@ -22,14 +26,41 @@ func main() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /{$}", homeHandler)
 	mux.HandleFunc("GET /cann", cannHandler)
+	mux.HandleFunc("GET /cann/{competition}", cannHandler)
+	mux.HandleFunc("GET /fixtures/{competition}", fixturesHandler)
+	mux.HandleFunc("GET /fixtures/{competition}/calendar", calendarHandler)
+	mux.HandleFunc("GET /results/{competition}", resultsHandler)
+	mux.HandleFunc("GET /scorers/{competition}", scorersHandler)
+	mux.HandleFunc("GET /scorers/{competition}/json", scorersJSONHandler)
+	mux.HandleFunc("GET /team/{id}", teamHandler)
 	mux.HandleFunc("GET /huxley", huxleyHandler)
 	mux.HandleFunc("GET /fpl", fplHandler)
+	mux.HandleFunc("GET /fpl/table", fplTableHandler)
+	mux.HandleFunc("GET /fpl/history", fplHistoryHandler)
+	mux.HandleFunc("GET /fpl/live", fplLiveHandler)
+	mux.HandleFunc("GET /fpl/awards", fplAwardsHandler)
+	mux.HandleFunc("GET /fpl/ownership", fplOwnershipHandler)
+	mux.HandleFunc("GET /fpl/leagues", fplLeaguesHandler)
+	mux.HandleFunc("GET /fpl/stream", fplStreamHandler)
+	mux.HandleFunc("GET /fpl/chart", fplChartHandler)
+	mux.HandleFunc("GET /fpl/transfers", fplTransfersHandler)
+	mux.HandleFunc("GET /fpl/{league}", fplHandler)
+	mux.HandleFunc("GET /fpl/{league}/table", fplTableHandler)
+	mux.HandleFunc("GET /fpl/{league}/history", fplHistoryHandler)
+	mux.HandleFunc("GET /fpl/{league}/live", fplLiveHandler)
+	mux.HandleFunc("GET /fpl/{league}/awards", fplAwardsHandler)
+	mux.HandleFunc("GET /fpl/{league}/ownership", fplOwnershipHandler)
+	mux.HandleFunc("GET /fpl/{league}/prizes", fplPrizesHandler)
+	mux.HandleFunc("GET /fpl/{league}/prizes/table", fplPrizesTableHandler)
+	mux.HandleFunc("GET /fpl/{league}/stream", fplStreamHandler)
+	mux.HandleFunc("GET /fpl/{league}/chart", fplChartHandler)
+	mux.HandleFunc("GET /fpl/{league}/transfers", fplTransfersHandler)
 
 	srv := http.Server{
 		ReadTimeout:  ServerReadTimeout,
 		WriteTimeout: ServerWriteTimeout,
 		Addr:         ":8080",
-		Handler:      mux,
+		Handler:      cors.Handler(mux, cors.DefaultConfig()),
 	}
 
 	log.Println("Listening on port 8080")
@@ -78,9 +109,128 @@ func fplHandler(w http.ResponseWriter, req *http.Request) {
 	fpl.Points(w, req)
 }
 
+// get the FPL league's weekly awards as json
+func fplAwardsHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Awards(w, req)
+}
+
+// get who owns and captains each player in the FPL league, and each manager's differentials, as json
+func fplOwnershipHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Ownership(w, req)
+}
+
+// stream the FPL league as server-sent events while its points change
+func fplStreamHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Stream(w, req)
+}
+
+// displays the FPL league's league and overall rank over the season as svg
+func fplChartHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Chart(w, req)
+}
+
+// get every transfer the FPL league's managers have made and the points they gained as json
+func fplTransfersHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Transfers(w, req)
+}
+
+// get a named FPL league's prize ledger as json
+func fplPrizesHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.PrizesJSON(w, req)
+}
+
+// displays a named FPL league's prize ledger as html
+func fplPrizesTableHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.PrizesTable(w, req)
+}
+
+// get the FPL leagues configured in the leagues file as json
+func fplLeaguesHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Leagues(w, req)
+}
+
+// displays FPL league table as html, for when the vercel app is unavailable
+func fplTableHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Table(w, req)
+}
+
+// get every FPL manager's gameweek history and past seasons as json
+func fplHistoryHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.History(w, req)
+}
+
+// get the FPL league scored from live player stats as json
+func fplLiveHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	fpl.Live(w, req)
+}
+
 // fetches the standard table standings, generates and outputs the Cann table
 func cannHandler(w http.ResponseWriter, req *http.Request) {
 	logRequest(req)
 
 	cann.GenerateTable(w, req)
 }
+
+// displays upcoming fixtures for a competition grouped by matchday
+func fixturesHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	matches.Fixtures(w, req)
+}
+
+// displays finished matches for a competition grouped by matchday
+func resultsHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	matches.Results(w, req)
+}
+
+// exports a team's upcoming fixtures as an iCalendar file
+func calendarHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	matches.Calendar(w, req)
+}
+
+// displays the top scorers leaderboard for a competition
+func scorersHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	scorers.Page(w, req)
+}
+
+// get the top scorers leaderboard for a competition as json
+func scorersJSONHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	scorers.JSON(w, req)
+}
+
+// displays a team's standing, fixtures and results, linked from the Cann table
+func teamHandler(w http.ResponseWriter, req *http.Request) {
+	logRequest(req)
+
+	team.Details(w, req)
+}
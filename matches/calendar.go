@@ -0,0 +1,115 @@
+package matches
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/footballdata"
+	"github.com/mick4711/moh/web"
+)
+
+const (
+	icalTimeFormat = "20060102T150405Z"
+	matchDuration  = 2 * time.Hour
+)
+
+var upcomingStatuses = []string{footballdata.StatusScheduled, footballdata.StatusTimed}
+
+// Calendar exports the upcoming fixtures for the team in query parameter "team"
+// as an iCalendar file, e.g. /fixtures/PL/calendar?team=64
+func Calendar(w http.ResponseWriter, req *http.Request) {
+	competition, ok := cann.Competition(req)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	teamID, err := strconv.Atoi(req.URL.Query().Get("team"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, "query parameter -team- must be a team id")
+
+		return
+	}
+
+	matches, err := getMatches(req.Context(), competition, upcomingStatuses)
+	if err != nil {
+		web.ReturnError(err, w)
+		return
+	}
+
+	teamMatches, teamName := filterTeam(matches, teamID)
+	if teamName == "" {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%d.ics", competition, teamID)))
+
+	if err := writeCalendar(w, teamName+" fixtures", teamMatches, time.Now()); err != nil {
+		web.ReturnError(err, w)
+	}
+}
+
+// the matches involving teamID and the team's name, empty if the team has no matches
+func filterTeam(matches []footballdata.Match, teamID int) (teamMatches []footballdata.Match, teamName string) {
+	for _, match := range matches {
+		switch teamID {
+		case match.HomeTeam.ID:
+			teamName = match.HomeTeam.ShortName
+		case match.AwayTeam.ID:
+			teamName = match.AwayTeam.ShortName
+		default:
+			continue
+		}
+
+		teamMatches = append(teamMatches, match)
+	}
+
+	return teamMatches, teamName
+}
+
+// write an RFC 5545 calendar with an event for each match, times are in UTC
+func writeCalendar(w io.Writer, name string, matches []footballdata.Match, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//moh//fixtures//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icalText(name),
+	}
+
+	for _, match := range matches {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:match-%d@moh", match.ID),
+			"DTSTAMP:"+now.UTC().Format(icalTimeFormat),
+			"DTSTART:"+match.UTCDate.UTC().Format(icalTimeFormat),
+			"DTEND:"+match.UTCDate.Add(matchDuration).UTC().Format(icalTimeFormat),
+			"SUMMARY:"+icalText(match.HomeTeam.ShortName+" v "+match.AwayTeam.ShortName),
+			"DESCRIPTION:"+icalText(fmt.Sprintf("%s %s", match.Competition.Name, matchdayName(match))),
+			"END:VEVENT",
+		)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	if err != nil {
+		return fmt.Errorf("error writing calendar: %w", err)
+	}
+
+	return nil
+}
+
+// escape text values
+func icalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
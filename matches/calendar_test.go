@@ -0,0 +1,59 @@
+package matches
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mick4711/moh/footballdata"
+)
+
+func TestFilterTeam(t *testing.T) {
+	teamMatches, teamName := filterTeam(testMatches(), liverpool.ID)
+	if teamName != "Liverpool" || len(teamMatches) != 2 || teamMatches[0].ID != 3 || teamMatches[1].ID != 2 {
+		t.Errorf("filterTeam(64) = %+v, %q", teamMatches, teamName)
+	}
+
+	if teamMatches, teamName := filterTeam(testMatches(), 1); teamName != "" || teamMatches != nil {
+		t.Errorf("filterTeam(1) = %+v, %q, want no matches", teamMatches, teamName)
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	match := footballdata.Match{
+		ID:          497590,
+		Competition: footballdata.Competition{Name: "Premier League"},
+		UTCDate:     time.Date(2025, 1, 14, 19, 30, 0, 0, time.UTC),
+		Matchday:    21,
+		HomeTeam:    liverpool,
+		AwayTeam:    footballdata.Team{ID: 1, ShortName: "Brighton, Hove"},
+	}
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	var sb strings.Builder
+	if err := writeCalendar(&sb, "Liverpool fixtures", []footballdata.Match{match}, now); err != nil {
+		t.Fatalf("writeCalendar() err = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//moh//fixtures//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Liverpool fixtures",
+		"BEGIN:VEVENT",
+		"UID:match-497590@moh",
+		"DTSTAMP:20250106T090000Z",
+		"DTSTART:20250114T193000Z",
+		"DTEND:20250114T213000Z",
+		`SUMMARY:Liverpool v Brighton\, Hove`,
+		"DESCRIPTION:Premier League Matchday 21",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+
+	if got := sb.String(); got != want {
+		t.Errorf("writeCalendar()\ngot :%q\nwant:%q", got, want)
+	}
+}
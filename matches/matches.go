@@ -0,0 +1,231 @@
+// Fixtures and results pages for a competition built from the football-data.org matches endpoint.
+// Matches are grouped by matchday with kickoff times shown in Europe/Dublin,
+// and each team links to its position on the competition's Cann table.
+package matches
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/footballdata"
+	"github.com/mick4711/moh/web"
+)
+
+const kickoffFormat = "Mon 2 Jan 15:04"
+
+var (
+	fixtureStatuses = []string{
+		footballdata.StatusScheduled,
+		footballdata.StatusTimed,
+		footballdata.StatusInPlay,
+		footballdata.StatusPaused,
+		footballdata.StatusPostponed,
+		footballdata.StatusSuspended,
+	}
+	resultStatuses = []string{footballdata.StatusFinished}
+)
+
+// A MatchRow contains the details displayed for a match
+type MatchRow struct {
+	Kickoff  string
+	HomeTeam footballdata.Team
+	AwayTeam footballdata.Team
+	Score    string
+	Status   string
+}
+
+// A Matchday contains the matches played on a matchday, or in a cup stage when there is no matchday
+type Matchday struct {
+	Name    string
+	Matches []MatchRow
+}
+
+// A Page contains the data for the matches template
+type Page struct {
+	Title           string
+	Competition     string
+	CompetitionName string
+	Timezone        string
+	Matchdays       []Matchday
+	Teams           []footballdata.Team // teams with upcoming fixtures, for calendar links
+}
+
+// Fixtures displays the upcoming matches for the competition in the request path
+func Fixtures(w http.ResponseWriter, req *http.Request) {
+	writeMatches(w, req, "Fixtures", fixtureStatuses, false)
+}
+
+// Results displays the finished matches for the competition in the request path, latest first
+func Results(w http.ResponseWriter, req *http.Request) {
+	writeMatches(w, req, "Results", resultStatuses, true)
+}
+
+// fetch the matches with statuses and write them to the response grouped by matchday
+func writeMatches(w http.ResponseWriter, req *http.Request, title string, statuses []string, latestFirst bool) {
+	competition, ok := cann.Competition(req)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	matches, err := getMatches(req.Context(), competition, statuses)
+	if err != nil {
+		web.ReturnError(err, w)
+		return
+	}
+
+	loc := web.Dublin()
+
+	page := Page{
+		Title:           title,
+		Competition:     competition,
+		CompetitionName: competition,
+		Timezone:        loc.String(),
+		Matchdays:       groupByMatchday(matches, loc, latestFirst),
+	}
+
+	if len(matches) > 0 {
+		page.CompetitionName = matches[0].Competition.Name
+	}
+
+	if !latestFirst {
+		page.Teams = teams(matches)
+	}
+
+	matchesTemplate := template.Must(template.ParseFiles("matches/MatchesTemplate.html"))
+	if err := matchesTemplate.Execute(w, page); err != nil {
+		web.ReturnError(fmt.Errorf("error executing matchesTemplate: %w", err), w)
+	}
+}
+
+// fetch the matches of a competition with statuses through the shared football-data client
+func getMatches(ctx context.Context, competition string, statuses []string) ([]footballdata.Match, error) {
+	client, err := footballdata.Shared()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := client.Matches(ctx, competition, footballdata.MatchFilter{Status: statuses})
+	if err != nil {
+		return nil, fmt.Errorf("error requesting matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// group matches by matchday, or stage, in kickoff order, reversed when latestFirst. A rearranged or postponed
+// match stays with its matchday, which are in matchday order, and stages are ordered by their first kickoff.
+func groupByMatchday(matches []footballdata.Match, loc *time.Location, latestFirst bool) []Matchday {
+	matches = slices.Clone(matches)
+	slices.SortStableFunc(matches, func(a, b footballdata.Match) int {
+		return a.UTCDate.Compare(b.UTCDate)
+	})
+
+	type group struct {
+		matchday int       // 0 for a stage
+		first    time.Time // earliest kickoff
+		Matchday
+	}
+
+	var groups []*group
+
+	byName := map[string]*group{}
+
+	for _, match := range matches {
+		name := matchdayName(match)
+
+		g, ok := byName[name]
+		if !ok {
+			g = &group{matchday: match.Matchday, first: match.UTCDate, Matchday: Matchday{Name: name}}
+			byName[name] = g
+			groups = append(groups, g)
+		}
+
+		g.Matches = append(g.Matches, MatchRow{
+			Kickoff:  match.UTCDate.In(loc).Format(kickoffFormat),
+			HomeTeam: match.HomeTeam,
+			AwayTeam: match.AwayTeam,
+			Score:    score(match.Score),
+			Status:   status(match.Status),
+		})
+	}
+
+	slices.SortStableFunc(groups, func(a, b *group) int {
+		if a.matchday > 0 && b.matchday > 0 {
+			return cmp.Compare(a.matchday, b.matchday)
+		}
+
+		return a.first.Compare(b.first)
+	})
+
+	matchdays := make([]Matchday, 0, len(groups))
+	for _, g := range groups {
+		if latestFirst {
+			slices.Reverse(g.Matches)
+		}
+
+		matchdays = append(matchdays, g.Matchday)
+	}
+
+	if latestFirst {
+		slices.Reverse(matchdays)
+	}
+
+	return matchdays
+}
+
+func matchdayName(match footballdata.Match) string {
+	if match.Matchday > 0 {
+		return fmt.Sprintf("Matchday %d", match.Matchday)
+	}
+
+	return status(match.Stage)
+}
+
+// full time score, or "v" before kickoff
+func score(s footballdata.Score) string {
+	if s.FullTime.Home == nil || s.FullTime.Away == nil {
+		return "v"
+	}
+
+	return fmt.Sprintf("%d - %d", *s.FullTime.Home, *s.FullTime.Away)
+}
+
+// e.g. IN_PLAY to In play
+func status(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	s = strings.ToLower(strings.ReplaceAll(s, "_", " "))
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// the distinct teams playing in matches, sorted by name
+func teams(matches []footballdata.Match) []footballdata.Team {
+	var teams []footballdata.Team
+
+	for _, match := range matches {
+		for _, team := range []footballdata.Team{match.HomeTeam, match.AwayTeam} {
+			if team.ID == 0 { // not yet decided in cup draws
+				continue
+			}
+
+			if !slices.ContainsFunc(teams, func(t footballdata.Team) bool { return t.ID == team.ID }) {
+				teams = append(teams, team)
+			}
+		}
+	}
+
+	slices.SortFunc(teams, func(a, b footballdata.Team) int { return strings.Compare(a.ShortName, b.ShortName) })
+
+	return teams
+}
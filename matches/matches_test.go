@@ -0,0 +1,111 @@
+package matches
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mick4711/moh/footballdata"
+)
+
+var (
+	liverpool = footballdata.Team{ID: 64, ShortName: "Liverpool"}
+	arsenal   = footballdata.Team{ID: 57, ShortName: "Arsenal"}
+	manCity   = footballdata.Team{ID: 65, ShortName: "Man City"}
+	tottenham = footballdata.Team{ID: 73, ShortName: "Tottenham"}
+)
+
+func goals(n int) *int {
+	return &n
+}
+
+func testMatches() []footballdata.Match {
+	return []footballdata.Match{
+		{
+			ID: 3, UTCDate: time.Date(2025, 1, 14, 19, 30, 0, 0, time.UTC), Status: footballdata.StatusTimed,
+			Matchday: 21, HomeTeam: liverpool, AwayTeam: arsenal,
+		},
+		{
+			ID: 1, UTCDate: time.Date(2024, 12, 29, 17, 30, 0, 0, time.UTC), Status: footballdata.StatusFinished,
+			Matchday: 19, HomeTeam: arsenal, AwayTeam: manCity,
+			Score: footballdata.Score{FullTime: footballdata.Goals{Home: goals(2), Away: goals(1)}},
+		},
+		{
+			ID: 4, UTCDate: time.Date(2025, 1, 15, 20, 0, 0, 0, time.UTC), Status: footballdata.StatusInPlay,
+			Matchday: 21, HomeTeam: manCity, AwayTeam: tottenham,
+			Score: footballdata.Score{FullTime: footballdata.Goals{Home: goals(0), Away: goals(0)}},
+		},
+		{
+			ID: 2, UTCDate: time.Date(2024, 12, 30, 16, 30, 0, 0, time.UTC), Status: footballdata.StatusFinished,
+			Matchday: 19, HomeTeam: tottenham, AwayTeam: liverpool,
+			Score: footballdata.Score{FullTime: footballdata.Goals{Home: goals(3), Away: goals(6)}},
+		},
+	}
+}
+
+func TestGroupByMatchday(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Skip("Europe/Dublin time zone not available")
+	}
+
+	want := []Matchday{
+		{"Matchday 19", []MatchRow{
+			{"Sun 29 Dec 17:30", arsenal, manCity, "2 - 1", "Finished"},
+			{"Mon 30 Dec 16:30", tottenham, liverpool, "3 - 6", "Finished"},
+		}},
+		{"Matchday 21", []MatchRow{
+			{"Tue 14 Jan 19:30", liverpool, arsenal, "v", "Timed"},
+			{"Wed 15 Jan 20:00", manCity, tottenham, "0 - 0", "In play"},
+		}},
+	}
+
+	if got := groupByMatchday(testMatches(), loc, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByMatchday()\ngot :%+v\nwant:%+v", got, want)
+	}
+
+	// latest first reverses matchdays and matches
+	got := groupByMatchday(testMatches(), loc, true)
+	if len(got) != 2 || got[0].Name != "Matchday 21" || got[0].Matches[0].HomeTeam.ID != manCity.ID {
+		t.Errorf("groupByMatchday() latest first = %+v", got)
+	}
+
+	// a rearranged matchday 19 match played after matchday 21 stays under matchday 19
+	rearranged := append(testMatches(), footballdata.Match{
+		ID: 5, UTCDate: time.Date(2025, 1, 22, 20, 0, 0, 0, time.UTC), Status: footballdata.StatusPostponed,
+		Matchday: 19, HomeTeam: liverpool, AwayTeam: manCity,
+	})
+	for _, latestFirst := range []bool{false, true} {
+		got := groupByMatchday(rearranged, loc, latestFirst)
+
+		names := []string{}
+		for _, matchday := range got {
+			names = append(names, matchday.Name)
+		}
+
+		want := []string{"Matchday 19", "Matchday 21"}
+		if latestFirst {
+			want = []string{"Matchday 21", "Matchday 19"}
+		}
+
+		if !reflect.DeepEqual(names, want) || len(got[slices.Index(want, "Matchday 19")].Matches) != 3 {
+			t.Errorf("groupByMatchday() rearranged latest first %v = %+v, want %v with 3 matches on matchday 19", latestFirst, got, want)
+		}
+	}
+
+	// summer kickoffs are an hour ahead of UTC in Dublin
+	summer := []footballdata.Match{{UTCDate: time.Date(2024, 8, 17, 11, 30, 0, 0, time.UTC), Stage: "LEAGUE_STAGE"}}
+	if got := groupByMatchday(summer, loc, false); got[0].Name != "League stage" || got[0].Matches[0].Kickoff != "Sat 17 Aug 12:30" {
+		t.Errorf("groupByMatchday() summer = %+v", got)
+	}
+}
+
+func TestTeams(t *testing.T) {
+	cupDraw := footballdata.Match{HomeTeam: liverpool, AwayTeam: footballdata.Team{}}
+	want := []footballdata.Team{arsenal, liverpool, manCity, tottenham}
+
+	if got := teams(append(testMatches(), cupDraw)); !reflect.DeepEqual(got, want) {
+		t.Errorf("teams() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+// Top scorers and assists leaderboard for a competition from the football-data.org scorers endpoint,
+// served as an html page and as json, optionally filtered to one team with query parameter "team".
+// Each scorer's club is shown with its position on the competition's Cann table.
+package scorers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/footballdata"
+	"github.com/mick4711/moh/web"
+)
+
+const (
+	scorersLimit   = 100 // enough scorers for a team filter to find every club's scorers
+	minutesInMatch = 90
+)
+
+// A Scorer contains the goal statistics of a player and their club's Cann table placing.
+// MinutesPerGoal is an estimate as football-data.org only provides matches played, each is counted as 90 minutes.
+type Scorer struct {
+	Name           string       `json:"name"`
+	TeamID         int          `json:"team_id"`
+	Team           string       `json:"team"`
+	Played         int          `json:"played"`
+	Goals          int          `json:"goals"`
+	Assists        int          `json:"assists"`
+	Penalties      int          `json:"penalties"`
+	MinutesPerGoal int          `json:"minutes_per_goal"`
+	Cann           cann.Placing `json:"cann"`
+}
+
+// Response contains the leaderboard for a competition
+type Response struct {
+	Competition     string   `json:"competition"`
+	CompetitionName string   `json:"competition_name"`
+	Timestamp       string   `json:"timestamp"`
+	Scorers         []Scorer `json:"scorers"`
+}
+
+// Page displays the leaderboard for the competition in the request path
+func Page(w http.ResponseWriter, req *http.Request) {
+	response, ok := leaderboard(w, req)
+	if !ok {
+		return
+	}
+
+	scorersTemplate := template.Must(template.ParseFiles("scorers/ScorersTemplate.html"))
+	if err := scorersTemplate.Execute(w, response); err != nil {
+		web.ReturnError(fmt.Errorf("error executing scorersTemplate: %w", err), w)
+	}
+}
+
+// JSON writes the leaderboard for the competition in the request path as json
+func JSON(w http.ResponseWriter, req *http.Request) {
+	response, ok := leaderboard(w, req)
+	if !ok {
+		return
+	}
+
+	body, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		web.ReturnError(err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, "%s\n", body)
+}
+
+// build the leaderboard for the request, errors are written to w and ok is false
+func leaderboard(w http.ResponseWriter, req *http.Request) (response Response, ok bool) {
+	competition, ok := cann.Competition(req)
+	if !ok {
+		http.NotFound(w, req)
+		return Response{}, false
+	}
+
+	var teamID int
+
+	if team := req.URL.Query().Get("team"); team != "" {
+		id, err := strconv.Atoi(team)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, "query parameter -team- must be a team id")
+
+			return Response{}, false
+		}
+
+		teamID = id
+	}
+
+	response, err := getLeaderboard(req.Context(), competition, teamID)
+	if err != nil {
+		web.ReturnError(err, w)
+		return Response{}, false
+	}
+
+	return response, true
+}
+
+// fetch the scorers and Cann placings for a competition, teamID 0 includes every team
+func getLeaderboard(ctx context.Context, competition string, teamID int) (Response, error) {
+	client, err := footballdata.Shared()
+	if err != nil {
+		return Response{}, err
+	}
+
+	scorersResponse, err := client.Scorers(ctx, competition, scorersLimit)
+	if err != nil {
+		return Response{}, fmt.Errorf("error requesting scorers: %w", err)
+	}
+
+	placings, err := cann.Placings(ctx, competition)
+	if err != nil {
+		return Response{}, err
+	}
+
+	response := Response{
+		Competition:     competition,
+		CompetitionName: scorersResponse.Competition.Name,
+		Timestamp:       time.Now().Format("Mon Jan _2 15:04:05 MST 2006"),
+		Scorers:         toScorers(scorersResponse.Scorers, placings, teamID),
+	}
+
+	return response, nil
+}
+
+// convert football-data scorers to leaderboard rows, teamID 0 includes every team
+func toScorers(topScorers []footballdata.Scorer, placings map[int]cann.Placing, teamID int) []Scorer {
+	scorers := []Scorer{}
+
+	for _, s := range topScorers {
+		if teamID != 0 && s.Team.ID != teamID {
+			continue
+		}
+
+		scorer := Scorer{
+			Name:      s.Player.Name,
+			TeamID:    s.Team.ID,
+			Team:      s.Team.ShortName,
+			Played:    s.PlayedMatches,
+			Goals:     s.Goals,
+			Assists:   valueOrZero(s.Assists),
+			Penalties: valueOrZero(s.Penalties),
+			Cann:      placings[s.Team.ID],
+		}
+
+		if s.Goals > 0 {
+			scorer.MinutesPerGoal = s.PlayedMatches * minutesInMatch / s.Goals
+		}
+
+		scorers = append(scorers, scorer)
+	}
+
+	return scorers
+}
+
+func valueOrZero(n *int) int {
+	if n == nil {
+		return 0
+	}
+
+	return *n
+}
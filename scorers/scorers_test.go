@@ -0,0 +1,57 @@
+package scorers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/footballdata"
+)
+
+func count(n int) *int {
+	return &n
+}
+
+func TestToScorers(t *testing.T) {
+	liverpool := footballdata.Team{ID: 64, ShortName: "Liverpool"}
+	manCity := footballdata.Team{ID: 65, ShortName: "Man City"}
+
+	topScorers := []footballdata.Scorer{
+		{
+			Player: footballdata.Person{Name: "Mohamed Salah"}, Team: liverpool,
+			PlayedMatches: 19, Goals: 18, Assists: count(13), Penalties: count(4),
+		},
+		{
+			Player: footballdata.Person{Name: "Erling Haaland"}, Team: manCity,
+			PlayedMatches: 20, Goals: 15, Penalties: count(2),
+		},
+		{
+			Player: footballdata.Person{Name: "Diogo Jota"}, Team: liverpool,
+			PlayedMatches: 12, Assists: count(2),
+		},
+	}
+
+	placings := map[int]cann.Placing{
+		64: {Position: 1, Points: 45, BehindLeader: 0},
+		65: {Position: 3, Points: 34, BehindLeader: 11},
+	}
+
+	salah := Scorer{"Mohamed Salah", 64, "Liverpool", 19, 18, 13, 4, 95, placings[64]}
+	haaland := Scorer{"Erling Haaland", 65, "Man City", 20, 15, 0, 2, 120, placings[65]}
+	jota := Scorer{"Diogo Jota", 64, "Liverpool", 12, 0, 2, 0, 0, placings[64]}
+
+	tests := []struct {
+		teamID int
+		want   []Scorer
+	}{
+		{0, []Scorer{salah, haaland, jota}},
+		{64, []Scorer{salah, jota}},
+		{1, []Scorer{}},
+	}
+
+	for _, test := range tests {
+		if got := toScorers(topScorers, placings, test.teamID); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("toScorers(team %d)\ngot :%+v\nwant:%+v", test.teamID, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Team details page linked from the Cann table, for any supported competition.
+// Shows the club's current standing and form, recent results, and remaining fixtures
+// with a difficulty rating derived from each opponent's position on the Cann table.
+package team
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/footballdata"
+	"github.com/mick4711/moh/web"
+)
+
+const (
+	dateFormat     = "Mon 2 Jan 15:04"
+	recentResults  = 5
+	maxDifficulty  = 5
+	venueHome      = "H"
+	venueAway      = "A"
+	winnerHomeTeam = "HOME_TEAM"
+	winnerDraw     = "DRAW"
+)
+
+// A Result is a finished match from the team's point of view
+type Result struct {
+	Date     string
+	Opponent footballdata.Team
+	Venue    string // H or A
+	Score    string
+	Outcome  string // W, D or L
+}
+
+// A Fixture is an upcoming match with the opponent's Cann table placing.
+// Difficulty runs from 1, opponent at the bottom of the Cann table, to 5, opponent level with the leader.
+type Fixture struct {
+	Date       string
+	Opponent   footballdata.Team
+	Venue      string
+	Placing    cann.Placing
+	Difficulty int
+}
+
+// A Page contains the data for the team template
+type Page struct {
+	Competition     string
+	CompetitionName string
+	Team            *footballdata.Team
+	Standing        footballdata.TableRow
+	Placing         cann.Placing
+	Form            string
+	Results         []Result
+	Fixtures        []Fixture
+	Competitions    []footballdata.Competition // other supported competitions the team plays in
+}
+
+// Details displays the team with id in the request path, in the competition from query parameter
+// "competition" or else the first supported league the team plays in
+func Details(w http.ResponseWriter, req *http.Request) {
+	teamID, err := strconv.Atoi(req.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	client, err := footballdata.Shared()
+	if err != nil {
+		web.ReturnError(err, w)
+		return
+	}
+
+	team, err := client.Team(req.Context(), teamID)
+	if err != nil {
+		web.ReturnError(fmt.Errorf("error requesting team %d: %w", teamID, err), w)
+		return
+	}
+
+	competition, ok := chooseCompetition(team.RunningCompetitions, strings.ToUpper(req.URL.Query().Get("competition")))
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	page, err := getPage(req.Context(), client, team, competition)
+	if err != nil {
+		web.ReturnError(err, w)
+		return
+	}
+
+	teamTemplate := template.Must(template.ParseFiles("team/TeamTemplate.html"))
+	if err := teamTemplate.Execute(w, page); err != nil {
+		web.ReturnError(fmt.Errorf("error executing teamTemplate: %w", err), w)
+	}
+}
+
+// the requested competition if the team plays in it, otherwise the first supported league,
+// otherwise the first supported competition. ok is false if none are supported.
+func chooseCompetition(running []footballdata.Competition, requested string) (code string, ok bool) {
+	var supported []footballdata.Competition
+
+	for _, competition := range running {
+		if footballdata.Supported(competition.Code) {
+			supported = append(supported, competition)
+		}
+	}
+
+	if len(supported) == 0 {
+		return "", false
+	}
+
+	if requested != "" {
+		found := slices.ContainsFunc(supported, func(c footballdata.Competition) bool { return c.Code == requested })
+		return requested, found
+	}
+
+	for _, competition := range supported {
+		if competition.Type == "LEAGUE" {
+			return competition.Code, true
+		}
+	}
+
+	return supported[0].Code, true
+}
+
+// gather standings, Cann placings and matches for the team in competition
+func getPage(ctx context.Context, client *footballdata.Client, team *footballdata.Team, competition string) (Page, error) {
+	standings, err := client.Standings(ctx, competition, footballdata.StandingsFilter{})
+	if err != nil {
+		return Page{}, fmt.Errorf("error requesting standings: %w", err)
+	}
+
+	placings, err := cann.Placings(ctx, competition)
+	if err != nil {
+		return Page{}, err
+	}
+
+	finished, err := client.TeamMatches(ctx, team.ID, footballdata.MatchFilter{
+		Status:       []string{footballdata.StatusFinished},
+		Competitions: []string{competition},
+	})
+	if err != nil {
+		return Page{}, fmt.Errorf("error requesting results: %w", err)
+	}
+
+	upcoming, err := client.TeamMatches(ctx, team.ID, footballdata.MatchFilter{
+		Status:       []string{footballdata.StatusScheduled, footballdata.StatusTimed},
+		Competitions: []string{competition},
+	})
+	if err != nil {
+		return Page{}, fmt.Errorf("error requesting fixtures: %w", err)
+	}
+
+	loc := web.Dublin()
+
+	page := Page{
+		Competition:     competition,
+		CompetitionName: standings.Competition.Name,
+		Team:            team,
+		Placing:         placings[team.ID],
+		Results:         toResults(team.ID, finished, loc),
+		Fixtures:        toFixtures(team.ID, upcoming, placings, loc),
+	}
+
+	for _, row := range standings.Table(footballdata.StandingTotal) {
+		if row.Team.ID == team.ID {
+			page.Standing = row
+		}
+	}
+
+	page.Form = page.Standing.Form
+	if page.Form == "" {
+		page.Form = form(page.Results)
+	}
+
+	for _, c := range team.RunningCompetitions {
+		if c.Code != competition && footballdata.Supported(c.Code) {
+			page.Competitions = append(page.Competitions, c)
+		}
+	}
+
+	return page, nil
+}
+
+// the most recent finished matches, latest first
+func toResults(teamID int, matches []footballdata.Match, loc *time.Location) []Result {
+	matches = slices.Clone(matches)
+	slices.SortFunc(matches, func(a, b footballdata.Match) int { return b.UTCDate.Compare(a.UTCDate) })
+
+	results := []Result{}
+
+	for _, match := range matches[:min(recentResults, len(matches))] {
+		opponent, venue := opponentOf(teamID, match)
+
+		result := Result{
+			Date:     match.UTCDate.In(loc).Format(dateFormat),
+			Opponent: opponent,
+			Venue:    venue,
+			Score:    score(match.Score),
+			Outcome:  "D",
+		}
+
+		switch {
+		case match.Score.Winner == winnerDraw:
+		case (match.Score.Winner == winnerHomeTeam) == (venue == venueHome):
+			result.Outcome = "W"
+		default:
+			result.Outcome = "L"
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// upcoming matches in kickoff order rated by the opponent's Cann table placing
+func toFixtures(teamID int, matches []footballdata.Match, placings map[int]cann.Placing, loc *time.Location) []Fixture {
+	matches = slices.Clone(matches)
+	slices.SortFunc(matches, func(a, b footballdata.Match) int { return a.UTCDate.Compare(b.UTCDate) })
+
+	var spread cann.Points
+	for _, placing := range placings {
+		spread = max(spread, placing.BehindLeader)
+	}
+
+	fixtures := []Fixture{}
+
+	for _, match := range matches {
+		opponent, venue := opponentOf(teamID, match)
+
+		fixture := Fixture{
+			Date:       match.UTCDate.In(loc).Format(dateFormat),
+			Opponent:   opponent,
+			Venue:      venue,
+			Difficulty: difficulty(cann.Placing{}, 0),
+		}
+
+		// opponents from outside the competition, or not yet drawn, keep the middle rating
+		if placing, ok := placings[opponent.ID]; ok {
+			fixture.Placing = placing
+			fixture.Difficulty = difficulty(placing, spread)
+		}
+
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures
+}
+
+// full time score, home team first
+func score(s footballdata.Score) string {
+	if s.FullTime.Home == nil || s.FullTime.Away == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d - %d", *s.FullTime.Home, *s.FullTime.Away)
+}
+
+// the other team in a match and whether it is a home or away game for teamID
+func opponentOf(teamID int, match footballdata.Match) (opponent footballdata.Team, venue string) {
+	if match.HomeTeam.ID == teamID {
+		return match.AwayTeam, venueHome
+	}
+
+	return match.HomeTeam, venueAway
+}
+
+// scale the opponent's points gap to the leader onto 1 to 5, where spread is the gap of the bottom team.
+// Everyone is level before a ball is kicked so every fixture is rated in the middle.
+func difficulty(opponent cann.Placing, spread cann.Points) int {
+	if spread == 0 {
+		return (maxDifficulty + 1) / 2 //nolint:gomnd //middle of the scale
+	}
+
+	gap := float64(opponent.BehindLeader) / float64(spread)
+
+	return maxDifficulty - int(math.Round(gap*(maxDifficulty-1)))
+}
+
+// form from the latest results, oldest first as in the standings
+func form(results []Result) string {
+	outcomes := make([]string, 0, len(results))
+	for i := len(results) - 1; i >= 0; i-- {
+		outcomes = append(outcomes, results[i].Outcome)
+	}
+
+	return strings.Join(outcomes, ",")
+}
@@ -0,0 +1,149 @@
+package team
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mick4711/moh/cann"
+	"github.com/mick4711/moh/footballdata"
+)
+
+var (
+	liverpool = footballdata.Team{ID: 64, ShortName: "Liverpool"}
+	arsenal   = footballdata.Team{ID: 57, ShortName: "Arsenal"}
+	manCity   = footballdata.Team{ID: 65, ShortName: "Man City"}
+	tottenham = footballdata.Team{ID: 73, ShortName: "Tottenham"}
+)
+
+func result(home, away footballdata.Team, day, homeGoals, awayGoals int, winner string) footballdata.Match {
+	return footballdata.Match{
+		UTCDate:  time.Date(2025, 1, day, 15, 0, 0, 0, time.UTC),
+		HomeTeam: home,
+		AwayTeam: away,
+		Score: footballdata.Score{
+			Winner:   winner,
+			FullTime: footballdata.Goals{Home: &homeGoals, Away: &awayGoals},
+		},
+	}
+}
+
+func TestChooseCompetition(t *testing.T) {
+	running := []footballdata.Competition{
+		{Code: "FAC", Type: "CUP"},
+		{Code: "CL", Type: "CUP"},
+		{Code: "PL", Type: "LEAGUE"},
+	}
+
+	tests := []struct {
+		running   []footballdata.Competition
+		requested string
+		want      string
+		wantOK    bool
+	}{
+		{running, "", "PL", true},
+		{running, "CL", "CL", true},
+		{running, "BL1", "BL1", false},
+		{running, "FAC", "FAC", false},
+		{running[:2], "", "CL", true},
+		{running[:1], "", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := chooseCompetition(test.running, test.requested)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("chooseCompetition(%v, %q) = %q, %v, want %q, %v", test.running, test.requested, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestToResults(t *testing.T) {
+	matches := []footballdata.Match{
+		result(liverpool, arsenal, 1, 2, 0, winnerHomeTeam),
+		result(tottenham, liverpool, 8, 3, 6, "AWAY_TEAM"),
+		result(liverpool, manCity, 15, 1, 1, winnerDraw),
+		result(manCity, liverpool, 22, 2, 0, winnerHomeTeam),
+		result(arsenal, liverpool, 29, 0, 1, "AWAY_TEAM"),
+		result(liverpool, tottenham, 4, 0, 1, "AWAY_TEAM"),
+	}
+
+	results := toResults(liverpool.ID, matches, time.UTC)
+
+	want := []Result{
+		{"Wed 29 Jan 15:00", arsenal, venueAway, "0 - 1", "W"},
+		{"Wed 22 Jan 15:00", manCity, venueAway, "2 - 0", "L"},
+		{"Wed 15 Jan 15:00", manCity, venueHome, "1 - 1", "D"},
+		{"Wed 8 Jan 15:00", tottenham, venueAway, "3 - 6", "W"},
+		{"Sat 4 Jan 15:00", tottenham, venueHome, "0 - 1", "L"},
+	}
+
+	if len(results) != len(want) {
+		t.Fatalf("toResults() = %d results, want %d", len(results), len(want))
+	}
+
+	for i := range want {
+		got := results[i]
+		if got.Date != want[i].Date || got.Opponent.ID != want[i].Opponent.ID || got.Venue != want[i].Venue ||
+			got.Score != want[i].Score || got.Outcome != want[i].Outcome {
+			t.Errorf("toResults()[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	if got := form(results); got != "L,W,D,L,W" {
+		t.Errorf("form() = %q, want L,W,D,L,W", got)
+	}
+}
+
+func TestToFixtures(t *testing.T) {
+	placings := map[int]cann.Placing{
+		64: {Position: 1, Points: 45, BehindLeader: 0},
+		57: {Position: 2, Points: 40, BehindLeader: 5},
+		65: {Position: 3, Points: 34, BehindLeader: 11},
+		73: {Position: 4, Points: 25, BehindLeader: 20},
+	}
+
+	matches := []footballdata.Match{
+		{UTCDate: time.Date(2025, 2, 1, 15, 0, 0, 0, time.UTC), HomeTeam: tottenham, AwayTeam: arsenal},
+		{UTCDate: time.Date(2025, 1, 14, 20, 0, 0, 0, time.UTC), HomeTeam: arsenal, AwayTeam: liverpool},
+		{UTCDate: time.Date(2025, 1, 25, 15, 0, 0, 0, time.UTC), HomeTeam: manCity, AwayTeam: arsenal},
+		{UTCDate: time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC), HomeTeam: arsenal, AwayTeam: footballdata.Team{}},
+	}
+
+	type rating struct {
+		opponent   int
+		venue      string
+		difficulty int
+	}
+
+	want := []rating{{64, venueHome, 5}, {65, venueAway, 3}, {73, venueAway, 1}, {0, venueHome, 3}}
+
+	var got []rating
+	for _, fixture := range toFixtures(arsenal.ID, matches, placings, time.UTC) {
+		got = append(got, rating{fixture.Opponent.ID, fixture.Venue, fixture.Difficulty})
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toFixtures()\ngot :%v\nwant:%v", got, want)
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	tests := []struct {
+		behindLeader cann.Points
+		spread       cann.Points
+		want         int
+	}{
+		{0, 20, 5},
+		{5, 20, 4},
+		{10, 20, 3},
+		{15, 20, 2},
+		{20, 20, 1},
+		{0, 0, 3},
+	}
+
+	for _, test := range tests {
+		if got := difficulty(cann.Placing{BehindLeader: test.behindLeader}, test.spread); got != test.want {
+			t.Errorf("difficulty(%d behind, spread %d) = %d, want %d", test.behindLeader, test.spread, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Package web has the helpers shared by the page handlers.
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// ReturnError logs the error and writes it with status internal server error
+// TODO display empty page template with error message
+func ReturnError(err error, w http.ResponseWriter) {
+	log.Printf("\n*********** FATAL ERROR ********** [%s]\n", err)
+	w.WriteHeader(http.StatusInternalServerError)
+	fmt.Fprintln(w, err)
+}
+
+// Dublin is the Irish time zone dates and kickoff times are shown in, UTC if it can not be loaded
+func Dublin() *time.Location {
+	loc, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return loc
+}
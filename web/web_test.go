@@ -0,0 +1,25 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReturnError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ReturnError(errors.New("no standings"), rec)
+
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "no standings") {
+		t.Errorf("ReturnError() = %d %q, want: 500 with the error", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDublin(t *testing.T) {
+	if loc := Dublin(); loc.String() != "Europe/Dublin" && loc != time.UTC {
+		t.Errorf("Dublin() = %v, want: Europe/Dublin or UTC", loc)
+	}
+}